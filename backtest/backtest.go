@@ -0,0 +1,248 @@
+// Package backtest replays synthetic incidents through the burn-rate alerts of an SLO,
+// measuring how fast each alert fires and resets and how much error budget is gone by then.
+package backtest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	selectors "unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// Incident is a synthetic shape of bad events, replayed after a clean history as long as the longest alert window
+type Incident struct {
+	Name     string
+	Duration time.Duration
+
+	// ErrorRatio is the share of bad events t into the incident, for an SLO whose error budget is errorBudget.
+	// It is capped at 1.
+	ErrorRatio func(t time.Duration, errorBudget float64) float64
+}
+
+// Incidents are the shapes replayed by default
+var Incidents = []Incident{
+	{
+		// Every request fails for half an hour
+		Name:       "sudden outage",
+		Duration:   30 * time.Minute,
+		ErrorRatio: func(time.Duration, float64) float64 { return 1 },
+	},
+	{
+		// The burn rate climbs steadily from 0 to 20x over 6 hours
+		Name:     "slow degradation",
+		Duration: 6 * time.Hour,
+		ErrorRatio: func(t time.Duration, errorBudget float64) float64 {
+			return 20 * errorBudget * float64(t) / float64(6*time.Hour)
+		},
+	},
+	{
+		// A 50x burn rate for 5 minutes
+		Name:       "short spike",
+		Duration:   5 * time.Minute,
+		ErrorRatio: func(_ time.Duration, errorBudget float64) float64 { return 50 * errorBudget },
+	},
+}
+
+// Result is how one alert of the policy behaved during an incident
+type Result struct {
+	Incident string
+	Alert    string
+	Severity string
+
+	// Detected is when the alert fired, from the start of the incident
+	Fired    bool
+	Detected time.Duration
+
+	// Reset is when the alert resolved, from the end of the incident
+	Resolved bool
+	Reset    time.Duration
+
+	// BudgetBeforeFiring is the share of the SLO's error budget the incident spent until the alert fired,
+	// IncidentBudget the share the whole incident spent
+	BudgetBeforeFiring float64
+	IncidentBudget     float64
+}
+
+// step is the scrape and evaluation interval of the replay
+const step = time.Minute
+
+// eventsPerStep is the traffic of the replay, constant throughout
+const eventsPerStep = 1000
+
+// Run replays every incident through the Prometheus alerts generated for the SLO's burn-rate policy.
+// The alerts read the raw selectors: build the SLO without recording rules.
+func Run(s slo.SLO, incidents []Incident) ([]Result, error) {
+	info := s.Info()
+	events, err := rules.NewEventSeries(info.SLI)
+	if err != nil {
+		return nil, err
+	}
+
+	var history, settle time.Duration
+	for _, alert := range info.BurnRate {
+		for _, w := range alert.Windows {
+			history = max(history, time.Duration(w.Long))
+			settle = max(settle, time.Duration(w.Short))
+		}
+	}
+
+	engine := promql.NewEngine(promql.EngineOpts{MaxSamples: 50_000_000, Timeout: time.Minute})
+	defer engine.Close()
+
+	var results []Result
+	for _, incident := range incidents {
+		r := &replay{info: info, events: events, incident: incident, history: history, settle: settle + 10*step}
+		incidentResults, err := r.run(engine, rules.AlertGroup(s, rules.Options{}))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", incident.Name, err)
+		}
+		results = append(results, incidentResults...)
+	}
+	return results, nil
+}
+
+type replay struct {
+	info     slo.Info
+	events   rules.EventSeries
+	incident Incident
+
+	// history is the clean traffic before the incident, settle the time left after it for the alerts to resolve
+	history time.Duration
+	settle  time.Duration
+}
+
+func (r *replay) start() time.Duration { return r.history }
+func (r *replay) end() time.Duration   { return r.history + r.incident.Duration }
+
+// errorRatio is the share of bad events counted by the sample at t, over the step before it
+func (r *replay) errorRatio(t time.Duration) float64 {
+	since := t - step - r.start()
+	if since < 0 || since >= r.incident.Duration {
+		return 0
+	}
+	return min(r.incident.ErrorRatio(since, 1-r.info.Target), 1)
+}
+
+// budget is the share of the error budget spent by the samples in (from, to]
+func (r *replay) budget(from, to time.Duration) float64 {
+	var ratios float64
+	for t := from + step; t <= to; t += step {
+		ratios += r.errorRatio(t)
+	}
+	windowSteps := float64(r.info.TimeWindow) / float64(step)
+	return ratios / (windowSteps * (1 - r.info.Target))
+}
+
+func (r *replay) run(engine *promql.Engine, group rules.Group) ([]Result, error) {
+	last := r.end() + r.settle
+	queryable := r.load(last)
+
+	results := make([]Result, 0, len(group.Rules))
+	for i, rule := range group.Rules {
+		active, err := r.activeSteps(engine, queryable, rule.Expr, last)
+		if err != nil {
+			return nil, fmt.Errorf("alert %s: %w", rule.Alert, err)
+		}
+		result := r.evaluate(r.info.BurnRate[i], active, last)
+		result.Severity = rule.Labels["severity"]
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// load builds the two counters of the SLI, one sample per step from 0 to last
+func (r *replay) load(last time.Duration) memoryStorage {
+	var counted, other []chunks.Sample
+	var countedTotal, otherTotal float64
+	for t := time.Duration(0); t <= last; t += step {
+		if t > 0 {
+			countedStep, otherStep := r.events.PerMinute(eventsPerStep, r.errorRatio(t))
+			countedTotal += countedStep
+			otherTotal += otherStep
+		}
+		counted = append(counted, floatSample{t: t.Milliseconds(), f: countedTotal})
+		other = append(other, floatSample{t: t.Milliseconds(), f: otherTotal})
+	}
+	return memoryStorage{
+		storage.NewListSeries(seriesLabels(r.events.Counted), counted),
+		storage.NewListSeries(seriesLabels(r.events.Other), other),
+	}
+}
+
+func seriesLabels(series selectors.Selector) labels.Labels {
+	b := labels.NewScratchBuilder(len(series.Matchers) + 1)
+	if series.Metric != "" {
+		b.Add(labels.MetricName, series.Metric)
+	}
+	for _, m := range series.Matchers {
+		b.Add(m.Label, m.Value)
+	}
+	b.Sort()
+	return b.Labels()
+}
+
+// activeSteps are the evaluations, from the start of the incident on, where the alert expression returned something
+func (r *replay) activeSteps(engine *promql.Engine, queryable storage.Queryable, expr string, last time.Duration) ([]time.Duration, error) {
+	query, err := engine.NewRangeQuery(context.Background(), queryable, nil, expr,
+		time.UnixMilli(r.start().Milliseconds()), time.UnixMilli(last.Milliseconds()), step)
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	result := query.Exec(context.Background())
+	if result.Err != nil {
+		return nil, result.Err
+	}
+	matrix, err := result.Matrix()
+	if err != nil {
+		return nil, err
+	}
+
+	var active []time.Duration
+	for _, series := range matrix {
+		for _, p := range series.Floats {
+			active = append(active, time.Duration(p.T)*time.Millisecond)
+		}
+	}
+	slices.Sort(active)
+	return slices.Compact(active), nil
+}
+
+// evaluate steps through the evaluations as Prometheus does: the alert fires once its
+// expression has held for the alert's For period, and resolves as soon as it stops holding
+func (r *replay) evaluate(alert slo.BurnRateAlert, active []time.Duration, last time.Duration) Result {
+	result := Result{
+		Incident:       r.incident.Name,
+		Alert:          alert.Name,
+		IncidentBudget: r.budget(r.start(), r.end()),
+	}
+
+	activeSince := time.Duration(-1)
+	for t := r.start(); t <= last; t += step {
+		if !slices.Contains(active, t) {
+			if result.Fired {
+				result.Resolved, result.Reset = true, t-r.end()
+				break
+			}
+			activeSince = -1
+			continue
+		}
+		if activeSince < 0 {
+			activeSince = t
+		}
+		if !result.Fired && t-activeSince >= alert.For {
+			result.Fired, result.Detected = true, t-r.start()
+			result.BudgetBeforeFiring = r.budget(r.start(), t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,46 @@
+package backtest
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+func TestRun(t *testing.T) {
+	s := slo.NewAvailabilitySLO("api-availability", "API Availability", "",
+		slo.Week*4, 0.999, `http_requests_total{job="api", code=~"5.."}`, `http_requests_total{job="api"}`)
+
+	results, err := Run(s, Incidents)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byAlert := map[string]Result{}
+	for _, r := range results {
+		byAlert[r.Incident+"/"+r.Alert] = r
+	}
+
+	// Every request failing: the 5m rate sees it one scrape later, then the alert is pending for 2m
+	outage := byAlert["sudden outage/SLOFastBurn"]
+	if !outage.Fired || outage.Detected != 3*time.Minute {
+		t.Errorf("outage detected = %t after %s, want after 3m", outage.Fired, outage.Detected)
+	}
+	// 30 failed minutes out of a 28d window allowing 0.1% of them
+	if want := 30 / (28 * 24 * 60 * 0.001); math.Abs(outage.IncidentBudget-want) > 1e-9 {
+		t.Errorf("outage budget = %g, want %g", outage.IncidentBudget, want)
+	}
+	if want := 3 / (28 * 24 * 60 * 0.001); math.Abs(outage.BudgetBeforeFiring-want) > 1e-9 {
+		t.Errorf("outage budget before firing = %g, want %g", outage.BudgetBeforeFiring, want)
+	}
+	// The 30m short window of the 6h condition keeps the alert up until it holds no failure
+	if !outage.Resolved || outage.Reset > 30*time.Minute {
+		t.Errorf("outage reset = %t after %s, want within 30m", outage.Resolved, outage.Reset)
+	}
+
+	for _, alert := range []string{"SLOFastBurn", "SLOSlowBurn"} {
+		if spike := byAlert["short spike/"+alert]; spike.Fired {
+			t.Errorf("a 5m spike fires %s after %s", alert, spike.Detected)
+		}
+	}
+}
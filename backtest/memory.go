@@ -0,0 +1,75 @@
+package backtest
+
+import (
+	"context"
+	"slices"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/chunkenc"
+	"github.com/prometheus/prometheus/tsdb/chunks"
+	"github.com/prometheus/prometheus/util/annotations"
+)
+
+// memoryStorage serves the replayed series to the PromQL engine straight from memory.
+// A replay holds two series of a few thousand samples, which doesn't call for a TSDB head.
+type memoryStorage []storage.Series
+
+func (m memoryStorage) Querier(_, _ int64) (storage.Querier, error) {
+	return m, nil
+}
+
+// Select returns the series every matcher matches; the engine trims them to the queried range itself
+func (m memoryStorage) Select(_ context.Context, sortSeries bool, _ *storage.SelectHints, matchers ...*labels.Matcher) storage.SeriesSet {
+	var selected []storage.Series
+	for _, series := range m {
+		lset := series.Labels()
+		if !slices.ContainsFunc(matchers, func(matcher *labels.Matcher) bool { return !matcher.Matches(lset.Get(matcher.Name)) }) {
+			selected = append(selected, series)
+		}
+	}
+	if sortSeries {
+		slices.SortFunc(selected, func(a, b storage.Series) int { return labels.Compare(a.Labels(), b.Labels()) })
+	}
+	return &seriesSet{series: selected}
+}
+
+func (m memoryStorage) LabelValues(context.Context, string, *storage.LabelHints, ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	return nil, nil, nil
+}
+
+func (m memoryStorage) LabelNames(context.Context, *storage.LabelHints, ...*labels.Matcher) ([]string, annotations.Annotations, error) {
+	return nil, nil, nil
+}
+
+func (m memoryStorage) Close() error {
+	return nil
+}
+
+type seriesSet struct {
+	series []storage.Series
+	next   int
+}
+
+func (s *seriesSet) Next() bool {
+	s.next++
+	return s.next <= len(s.series)
+}
+
+func (s *seriesSet) At() storage.Series                { return s.series[s.next-1] }
+func (s *seriesSet) Err() error                        { return nil }
+func (s *seriesSet) Warnings() annotations.Annotations { return nil }
+
+// floatSample is a sample of a counter, as stored by storage.NewListSeries
+type floatSample struct {
+	t int64
+	f float64
+}
+
+func (s floatSample) T() int64                      { return s.t }
+func (s floatSample) F() float64                    { return s.f }
+func (s floatSample) H() *histogram.Histogram       { return nil }
+func (s floatSample) FH() *histogram.FloatHistogram { return nil }
+func (s floatSample) Type() chunkenc.ValueType      { return chunkenc.ValFloat }
+func (s floatSample) Copy() chunks.Sample           { return s }
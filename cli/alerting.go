@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/slo"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+// Where the burn-rate alerts are evaluated
+const (
+	alertsPrometheus = "prometheus"
+	alertsGrafana    = "grafana"
+	alertsBoth       = "both"
+)
+
+// alertingOptions configure the generated alerts
+type alertingOptions struct {
+	mode     string
+	folder   string
+	interval string
+	labels   keyValues
+}
+
+func (o *options) checkAlerting() error {
+	switch o.alerting.mode {
+	case alertsPrometheus, alertsGrafana, alertsBoth:
+	default:
+		return fmt.Errorf("-alerts must be %q, %q or %q, not %q", alertsPrometheus, alertsGrafana, alertsBoth, o.alerting.mode)
+	}
+	if d, err := time.ParseDuration(o.alerting.interval); err != nil || d < time.Second {
+		return fmt.Errorf("-alert-interval %q must be a duration of at least 1s, such as 1m", o.alerting.interval)
+	}
+	return nil
+}
+
+func (o *options) ruleOptions() rules.Options {
+	return rules.Options{GrafanaURL: o.grafanaURL, Labels: o.alerting.labels}
+}
+
+// prometheusAlerts tells whether the rule files hold the alerts or only the recording rules
+func (o *options) prometheusAlerts() bool {
+	return o.alerting.mode != alertsGrafana
+}
+
+// grafanaAlertGroup builds the Grafana-managed alerts of an SLO, stored with its dashboard unless -alert-folder is set
+func (o *options) grafanaAlertGroup(s *spec.SLO, generator slo.SLO) (grafana.AlertRuleGroup, error) {
+	folder := o.alerting.folder
+	if folder == "" {
+		folder = folderTitle(s)
+	}
+	group, err := rules.GrafanaAlertGroup(generator, folder, o.alerting.interval, o.ruleOptions())
+	if err != nil {
+		return grafana.AlertRuleGroup{}, fmt.Errorf("building Grafana alert rules: %w: set datasource.uid in the spec", err)
+	}
+	return group, nil
+}
+
+// grafanaAlertArtifact renders the Grafana-managed alerts of an SLO as a provisioning file
+func (o *options) grafanaAlertArtifact(s *spec.SLO, generator slo.SLO) (artifact, error) {
+	group, err := o.grafanaAlertGroup(s, generator)
+	if err != nil {
+		return artifact{}, err
+	}
+	alerting, err := grafana.NewAlertingProvisioning(group).ToYAML()
+	if err != nil {
+		return artifact{}, fmt.Errorf("building Grafana alert rules: %w", err)
+	}
+
+	dir := "alerting"
+	if o.format == formatProvisioning {
+		dir = filepath.Join("provisioning", "alerting")
+	}
+	return artifact{path: filepath.Join(dir, s.UID+".yaml"), content: alerting}, nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/grafana"
+)
+
+func runPlan(args []string, stdout, stderr io.Writer) int {
+	return runPlanOrApply("plan", false, args, stdout, stderr)
+}
+
+func runApply(args []string, stdout, stderr io.Writer) int {
+	return runPlanOrApply("apply", true, args, stdout, stderr)
+}
+
+// runPlanOrApply compares the generated dashboards with the deployed ones and,
+// when applying, publishes the ones that differ
+func runPlanOrApply(name string, apply bool, args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet(name, stderr, opts)
+	opts.addBuildFlags(fs)
+	opts.addGrafanaFlags(fs)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	client, err := opts.grafanaClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	plans := client.Plan(context.Background(), opts.folder, buildDashboards(slos, r))
+
+	var pending []grafana.Dashboard
+	counts := map[grafana.Action]int{}
+	for _, p := range plans {
+		if p.Err != nil {
+			r.fail(p.Dashboard.UID, p.Err)
+			continue
+		}
+		counts[p.Action]++
+		printPlan(stdout, p)
+		if p.Action != grafana.ActionNone {
+			pending = append(pending, p.Dashboard)
+		}
+	}
+	fmt.Fprintf(stdout, "plan: %d to create, %d to update, %d unchanged\n",
+		counts[grafana.ActionCreate], counts[grafana.ActionUpdate], counts[grafana.ActionNone])
+
+	if apply {
+		published := publish(stdout, r, grafana.NewPublisher(client, opts.folder), pending)
+		fmt.Fprintf(stdout, "applied %d dashboard(s) to %s\n", published, client.URL)
+		return r.print(stderr)
+	}
+
+	if code := r.print(stderr); code != ExitOK {
+		return code
+	}
+	if len(pending) > 0 {
+		return ExitChanges
+	}
+	return ExitOK
+}
+
+func printPlan(w io.Writer, p grafana.Plan) {
+	switch p.Action {
+	case grafana.ActionCreate:
+		fmt.Fprintf(w, "+ %s (create)\n", p.Dashboard.UID)
+	case grafana.ActionUpdate:
+		fmt.Fprintf(w, "~ %s (update)\n", p.Dashboard.UID)
+		for _, c := range p.Changes {
+			fmt.Fprintf(w, "    %s\n", c)
+			if c.Before != c.After {
+				printExpr(w, "-", c.Before)
+				printExpr(w, "+", c.After)
+			}
+		}
+	}
+}
+
+func printExpr(w io.Writer, sign, expr string) {
+	if expr == "" {
+		return
+	}
+	fmt.Fprintf(w, "        %s %s\n", sign, strings.ReplaceAll(expr, "\n", "\n          "))
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/slo"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+// Output formats of generate and diff
+const (
+	formatFiles        = "files"
+	formatProvisioning = "provisioning"
+	formatKubernetes   = "kubernetes"
+	formatTerraform    = "terraform"
+)
+
+func (o *options) checkFormat() error {
+	switch o.format {
+	case formatFiles, formatProvisioning, formatKubernetes, formatTerraform:
+		return o.checkAlerting()
+	}
+	return fmt.Errorf("-format must be %q, %q, %q or %q, not %q",
+		formatFiles, formatProvisioning, formatKubernetes, formatTerraform, o.format)
+}
+
+// artifact is a generated file; path is relative to the output directory
+type artifact struct {
+	path    string
+	content string
+}
+
+// buildArtifacts generates every file produced for a single SLO
+func buildArtifacts(s *spec.SLO, opts *options) ([]artifact, error) {
+	generator, err := s.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	dashboardJSON, err := generator.BuildJSON()
+	if err != nil {
+		return nil, fmt.Errorf("building dashboard: %w", err)
+	}
+
+	if opts.format == formatTerraform {
+		return terraformArtifacts(s, opts, generator, dashboardJSON)
+	}
+
+	groups := []rules.Group{
+		rules.RecordingGroup(generator),
+		rules.AlertGroup(generator, opts.ruleOptions()),
+	}
+	if opts.format == formatKubernetes {
+		return kubernetesArtifacts(s, opts, dashboardJSON, groups)
+	}
+
+	if !opts.prometheusAlerts() {
+		groups = groups[:1]
+	}
+	rulesYAML, err := rules.NewFile(groups...).ToYAML()
+	if err != nil {
+		return nil, fmt.Errorf("building rules: %w", err)
+	}
+
+	artifacts := []artifact{
+		{path: opts.dashboardPath(s), content: dashboardJSON},
+		{path: filepath.Join("rules", s.UID+".yaml"), content: rulesYAML},
+	}
+	if opts.prometheusAlerts() {
+		tests, err := ruleTestsArtifact(s, opts, generator)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, tests)
+	}
+	if opts.alerting.mode != alertsPrometheus {
+		alerting, err := opts.grafanaAlertArtifact(s, generator)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, alerting)
+	}
+	return artifacts, nil
+}
+
+// ruleTestsArtifact is the promtool test file of the SLO's alerts, kept out of rules/*.yaml
+// so that Prometheus doesn't load it as a rule file
+func ruleTestsArtifact(s *spec.SLO, opts *options, generator slo.SLO) (artifact, error) {
+	tests, err := rules.AlertTests(generator, filepath.Join("..", s.UID+".yaml"), opts.ruleOptions())
+	if err != nil {
+		return artifact{}, fmt.Errorf("building rule tests: %w", err)
+	}
+	content, err := tests.ToYAML()
+	if err != nil {
+		return artifact{}, fmt.Errorf("building rule tests: %w", err)
+	}
+	return artifact{path: filepath.Join("rules", "tests", s.UID+".yaml"), content: content}, nil
+}
+
+// buildDashboard generates only the dashboard of a single SLO, for commands talking to Grafana
+func buildDashboard(s *spec.SLO) (grafana.Dashboard, error) {
+	generator, err := s.Build()
+	if err != nil {
+		return grafana.Dashboard{}, err
+	}
+
+	dashboardJSON, err := generator.BuildJSON()
+	if err != nil {
+		return grafana.Dashboard{}, fmt.Errorf("building dashboard: %w", err)
+	}
+	return grafana.Dashboard{UID: s.UID, JSON: dashboardJSON}, nil
+}
@@ -0,0 +1,122 @@
+//go:build backtest
+
+// The backtest command links the PromQL engine, which more than doubles the size of the binary:
+// it is only built in with -tags backtest.
+
+package cli
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"unobravo.com/go-obs-as-code/backtest"
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/slo"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+func init() {
+	commands = append(commands, command{"backtest", backtestSummary, runBacktest})
+}
+
+// policy is a burn-rate policy to replay, nil alerts standing for each SLO's own
+type policy struct {
+	name   string
+	alerts []spec.BurnRateAlert
+}
+
+// paths is a repeatable flag of file paths
+type paths []string
+
+func (p *paths) String() string {
+	return strings.Join(*p, ",")
+}
+
+func (p *paths) Set(value string) error {
+	*p = append(*p, value)
+	return nil
+}
+
+func runBacktest(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	var policyFiles paths
+	fs := newFlagSet("backtest", stderr, opts)
+	fs.Var(&policyFiles, "policy", "spec file whose file-level burn_rate is replayed next to each SLO's own policy (repeatable)")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	policies := []policy{{name: "current"}}
+	for _, path := range policyFiles {
+		alerts, err := spec.LoadPolicy(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "error: %v\n", err)
+			return ExitUsage
+		}
+		policies = append(policies, policy{name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), alerts: alerts})
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	for i, s := range slos {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		fmt.Fprintf(stdout, "%s: %s over %s\n", s.UID, slo.FormatPercent(s.Target), s.Window)
+
+		tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "POLICY\tINCIDENT\tALERT\tSEVERITY\tDETECTED\tRESET AFTER RECOVERY\tBUDGET BEFORE FIRING\tINCIDENT BUDGET")
+		for _, p := range policies {
+			results, err := backtestPolicy(s, p)
+			if err != nil {
+				r.fail(s.UID, fmt.Errorf("policy %s: %w", p.name, err))
+				continue
+			}
+			for _, result := range results {
+				detected, reset, before := "never", "-", "-"
+				if result.Fired {
+					detected = rules.FormatDuration(result.Detected.Round(time.Minute))
+					reset = "still firing"
+					if result.Resolved {
+						reset = rules.FormatDuration(result.Reset.Round(time.Minute))
+					}
+					before = formatBudget(result.BudgetBeforeFiring)
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", p.name, result.Incident, result.Alert, result.Severity,
+					detected, reset, before, formatBudget(result.IncidentBudget))
+			}
+		}
+		tw.Flush()
+	}
+
+	return r.print(stderr)
+}
+
+// backtestPolicy replays the default incidents through the SLO's alerts under the given policy.
+// The alerts read the raw selectors, the replay having no recording rules to evaluate.
+func backtestPolicy(s *spec.SLO, p policy) ([]backtest.Result, error) {
+	variant := *s
+	variant.RecordingRules = false
+	if p.alerts != nil {
+		variant.BurnRate = p.alerts
+	}
+
+	generator, err := variant.Build()
+	if err != nil {
+		return nil, err
+	}
+	return backtest.Run(generator, backtest.Incidents)
+}
+
+func formatBudget(share float64) string {
+	return fmt.Sprintf("%.2f%%", share*100)
+}
@@ -0,0 +1,17 @@
+//go:build !backtest
+
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+func init() {
+	commands = append(commands, command{"backtest", backtestSummary + " (needs a build with -tags backtest)", runBacktestDisabled})
+}
+
+func runBacktestDisabled(_ []string, _, stderr io.Writer) int {
+	fmt.Fprintln(stderr, "error: this binary is built without the backtest command, rebuild it with: go build -tags backtest")
+	return ExitUsage
+}
@@ -0,0 +1,214 @@
+package cli
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+// Exit codes returned by Run
+const (
+	ExitOK      = 0
+	ExitFailure = 1
+	ExitUsage   = 2
+	ExitChanges = 3
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout, stderr io.Writer) int
+}
+
+var commands = []command{
+	{"generate", "build dashboards and alerting rules from the SLO specs into the output directory", runGenerate},
+	{"validate", "check the SLO specs and that everything builds, without writing anything", runValidate},
+	{"list", "list the SLOs defined in the spec directory", runList},
+	{"diff", "show how the generated files differ from the output directory", runDiff},
+	{"publish", "push the dashboards to Grafana, overwriting them by UID", runPublish},
+	{"plan", "show how the generated dashboards differ from the ones deployed in Grafana", runPlan},
+	{"apply", "push only the dashboards that differ from the ones deployed in Grafana", runApply},
+	{"prune", "list or delete managed dashboards in Grafana whose SLO no longer exists", runPrune},
+}
+
+// backtestSummary describes the backtest command, registered by backtest.go or backtest_disabled.go
+// depending on the backtest build tag
+const backtestSummary = "replay synthetic incidents through the burn-rate alerts and report how fast they fire and reset"
+
+// Run executes the command line described by args and returns the process exit code
+func Run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return ExitUsage
+	}
+
+	switch args[0] {
+	case "help", "-h", "-help", "--help":
+		usage(stdout)
+		return ExitOK
+	}
+
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdout, stderr)
+		}
+	}
+
+	fmt.Fprintf(stderr, "unknown command %q\n\n", args[0])
+	usage(stderr)
+	return ExitUsage
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: go-obs-as-code <command> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'go-obs-as-code <command> -h' for the flags of a command.")
+}
+
+// options are the flags shared by the commands
+type options struct {
+	specDir    string
+	outputDir  string
+	selected   string
+	grafanaURL string
+	datasource spec.Datasource
+
+	format           string
+	provisioningPath string
+	kubernetes       kubernetesOptions
+	alerting         alertingOptions
+
+	grafanaToken string
+	folder       string
+	timeout      time.Duration
+}
+
+func newFlagSet(name string, stderr io.Writer, opts *options) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.specDir, "specs", "slos", "directory containing the SLO spec files")
+	fs.StringVar(&opts.selected, "slo", "", "comma separated list of SLO UIDs to act on (default all)")
+	return fs
+}
+
+// addBuildFlags registers the flags of commands that build the SLOs
+func (o *options) addBuildFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.grafanaURL, "grafana-url", "", "Grafana base URL, used to link alerts to their dashboard and to publish to")
+	fs.StringVar(&o.datasource.UID, "datasource-uid", "", "datasource UID for SLOs whose spec doesn't set one (default grafanacloud-prom)")
+	fs.StringVar(&o.datasource.Type, "datasource-type", "prometheus", "datasource plugin type for SLOs whose spec doesn't set one")
+	fs.BoolVar(&o.datasource.Variable, "datasource-variable", false, "query a ${datasource} dashboard variable for SLOs whose spec doesn't set a datasource")
+}
+
+// addOutputFlags registers the flags of commands that read or write the output directory
+func (o *options) addOutputFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.outputDir, "out", "output", "directory the generated files are written to")
+	fs.StringVar(&o.format, "format", formatFiles, "layout of the output directory: files, provisioning for Grafana file provisioning, kubernetes for operator manifests, or terraform for Grafana provider resources")
+	fs.StringVar(&o.provisioningPath, "provisioning-path", "/var/lib/grafana/slo", "where the output directory is mounted in the Grafana container, with -format provisioning")
+	fs.StringVar(&o.alerting.mode, "alerts", alertsPrometheus, "where the burn-rate alerts are evaluated: prometheus, grafana for Grafana-managed rules, or both (terraform always uses grafana, kubernetes prometheus)")
+	fs.StringVar(&o.alerting.folder, "alert-folder", "", "Grafana folder of the Grafana-managed alerts (default the folder of their dashboard)")
+	fs.StringVar(&o.alerting.interval, "alert-interval", "1m", "evaluation interval of the Grafana-managed alerts")
+	fs.Var(&o.alerting.labels, "alert-label", "key=value label added to every alert, for notification routing (repeatable)")
+	fs.StringVar(&o.kubernetes.namespace, "namespace", "monitoring", "namespace of the manifests, with -format kubernetes")
+	fs.Var(&o.kubernetes.labels, "label", "key=value label of the manifests, with -format kubernetes (repeatable)")
+	fs.Var(&o.kubernetes.annotations, "annotation", "key=value annotation of the manifests, with -format kubernetes (repeatable)")
+	fs.Var(&o.kubernetes.instanceSelector, "instance-selector", "key=value label of the Grafana instances to deploy dashboards to, with -format kubernetes (repeatable, default dashboards=grafana)")
+}
+
+// parseFlags parses args and returns the exit code to stop with, if any
+func parseFlags(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return ExitOK, true
+		}
+		return ExitUsage, true
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(fs.Output(), "unexpected arguments: %s\n", strings.Join(fs.Args(), " "))
+		return ExitUsage, true
+	}
+	return 0, false
+}
+
+// load reads the spec directory and applies the -slo selection.
+// Broken spec files are recorded in the report and the remaining SLOs are returned.
+func (o *options) load(r *report) ([]*spec.SLO, error) {
+	slos, err := spec.LoadDir(o.specDir)
+	if slos == nil && err != nil {
+		return nil, err
+	}
+	r.failAll("", err)
+
+	// Manifests are named after the SLO, so colliding names would overwrite each other's,
+	// even those of SLOs left out by -slo
+	if o.format == formatKubernetes {
+		if err := checkKubernetesNames(slos); err != nil {
+			return nil, err
+		}
+	}
+
+	if o.selected == "" {
+		return slos, nil
+	}
+
+	byUID := map[string]*spec.SLO{}
+	for _, s := range slos {
+		byUID[s.UID] = s
+	}
+
+	var selected []*spec.SLO
+	var missing []string
+	for _, uid := range strings.Split(o.selected, ",") {
+		uid = strings.TrimSpace(uid)
+		if uid == "" {
+			continue
+		}
+		s, ok := byUID[uid]
+		if !ok {
+			missing = append(missing, uid)
+			continue
+		}
+		selected = append(selected, s)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("unknown SLO %s", strings.Join(missing, ", "))
+	}
+	return selected, nil
+}
+
+// loadValid loads the selected SLOs and keeps only the ones that pass validation
+func (o *options) loadValid(r *report) ([]*spec.SLO, error) {
+	slos, err := o.load(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Duplicate UIDs would overwrite each other's output, so nothing is built
+	if err := spec.CheckUnique(slos); err != nil {
+		r.failAll("", err)
+		return nil, nil
+	}
+
+	var valid []*spec.SLO
+	for _, s := range slos {
+		if s.Datasource == nil && (o.datasource.UID != "" || o.datasource.Variable) {
+			s.Datasource = &o.datasource
+		}
+		if err := s.Validate(); err != nil {
+			r.fail("", err)
+			continue
+		}
+		valid = append(valid, s)
+	}
+	return valid, nil
+}
@@ -0,0 +1,126 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"unobravo.com/go-obs-as-code/internal/textdiff"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+func runDiff(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	flags := newFlagSet("diff", stderr, opts)
+	opts.addBuildFlags(flags)
+	opts.addOutputFlags(flags)
+	if code, stop := parseFlags(flags, args); stop {
+		return code
+	}
+	if err := opts.checkFormat(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	changed := 0
+	expected := map[string]bool{}
+	var built []*spec.SLO
+	for _, s := range slos {
+		artifacts, err := buildArtifacts(s, opts)
+		if err != nil {
+			r.fail(s.UID, err)
+			continue
+		}
+		built = append(built, s)
+
+		n, err := diffArtifacts(stdout, opts.outputDir, artifacts, expected)
+		changed += n
+		if err != nil {
+			r.fail(s.UID, err)
+		}
+	}
+
+	// Stale files and tree-wide files only make sense when every SLO was considered
+	if opts.selected == "" {
+		artifacts, err := opts.treeArtifacts(built)
+		if err == nil {
+			var n int
+			n, err = diffArtifacts(stdout, opts.outputDir, artifacts, expected)
+			changed += n
+		}
+		if err != nil {
+			r.fail("", err)
+		}
+
+		stale, err := staleFiles(opts.outputDir, expected)
+		if err != nil {
+			r.fail("", err)
+		}
+		for _, path := range stale {
+			fmt.Fprintf(stdout, "stale: %s\n", path)
+			changed++
+		}
+	}
+
+	fmt.Fprintf(stdout, "%d file(s) differ from %s\n", changed, opts.outputDir)
+	if code := r.print(stderr); code != ExitOK {
+		return code
+	}
+	if changed > 0 {
+		return ExitChanges
+	}
+	return ExitOK
+}
+
+// diffArtifacts prints how the artifacts differ from the output directory, marks them as expected
+// and returns how many differ
+func diffArtifacts(stdout io.Writer, outputDir string, artifacts []artifact, expected map[string]bool) (int, error) {
+	changed := 0
+	for _, a := range artifacts {
+		outputFile := filepath.Join(outputDir, a.path)
+		expected[outputFile] = true
+
+		current, err := os.ReadFile(outputFile)
+		if errors.Is(err, fs.ErrNotExist) {
+			fmt.Fprintf(stdout, "new: %s\n", outputFile)
+			changed++
+			continue
+		}
+		if err != nil {
+			return changed, err
+		}
+
+		if textdiff.Unified(stdout, outputFile, outputFile+" (generated)", string(current), a.content) {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// staleFiles lists files in the output directory that no SLO generates anymore
+func staleFiles(outputDir string, expected map[string]bool) ([]string, error) {
+	var stale []string
+	err := filepath.WalkDir(outputDir, func(path string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) && path == outputDir {
+			return fs.SkipAll
+		}
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && !expected[path] {
+			stale = append(stale, path)
+		}
+		return nil
+	})
+	return stale, err
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+func runGenerate(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet("generate", stderr, opts)
+	opts.addBuildFlags(fs)
+	opts.addOutputFlags(fs)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+	if err := opts.checkFormat(); err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	generated := 0
+	var built []*spec.SLO
+	for _, s := range slos {
+		artifacts, err := buildArtifacts(s, opts)
+		if err != nil {
+			r.fail(s.UID, err)
+			continue
+		}
+
+		if err := writeArtifacts(stdout, opts.outputDir, artifacts); err != nil {
+			r.fail(s.UID, err)
+			continue
+		}
+		built = append(built, s)
+		generated++
+	}
+
+	// Files describing the whole tree would lose the SLOs left out by -slo
+	if opts.selected == "" {
+		artifacts, err := opts.treeArtifacts(built)
+		if err == nil {
+			err = writeArtifacts(stdout, opts.outputDir, artifacts)
+		}
+		if err != nil {
+			r.fail("", err)
+		}
+	}
+
+	fmt.Fprintf(stdout, "generated %d SLO(s) in %s\n", generated, opts.outputDir)
+	return r.print(stderr)
+}
+
+func writeArtifacts(stdout io.Writer, outputDir string, artifacts []artifact) error {
+	for _, a := range artifacts {
+		outputFile := filepath.Join(outputDir, a.path)
+		if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(outputFile, []byte(a.content), 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stdout, "wrote %s\n", outputFile)
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/kubernetes"
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+// kubernetesOptions configure the manifests of the kubernetes format
+type kubernetesOptions struct {
+	namespace        string
+	labels           keyValues
+	annotations      keyValues
+	instanceSelector keyValues
+}
+
+// kubernetesArtifacts wraps the dashboard and the rules of an SLO in GrafanaDashboard and PrometheusRule manifests
+func kubernetesArtifacts(s *spec.SLO, opts *options, dashboardJSON string, groups []rules.Group) ([]artifact, error) {
+	k := opts.kubernetes
+	meta := kubernetes.ObjectMeta{
+		Name:        kubernetes.Name(s.UID),
+		Namespace:   k.namespace,
+		Labels:      maps.Clone(k.labels),
+		Annotations: maps.Clone(k.annotations),
+	}
+
+	selector := k.instanceSelector
+	if len(selector) == 0 {
+		selector = keyValues{"dashboards": "grafana"}
+	}
+
+	dashboard, err := kubernetes.ToYAML(kubernetes.NewGrafanaDashboard(meta, selector, folderTitle(s), dashboardJSON))
+	if err != nil {
+		return nil, fmt.Errorf("building GrafanaDashboard: %w", err)
+	}
+	rule, err := kubernetes.ToYAML(kubernetes.NewPrometheusRule(meta, groups...))
+	if err != nil {
+		return nil, fmt.Errorf("building PrometheusRule: %w", err)
+	}
+
+	return []artifact{
+		{path: filepath.Join("kubernetes", "dashboards", meta.Name+".yaml"), content: dashboard},
+		{path: filepath.Join("kubernetes", "rules", meta.Name+".yaml"), content: rule},
+	}, nil
+}
+
+// checkKubernetesNames reports every SLO whose object name was already taken by an earlier one:
+// names fold case and invalid characters, so distinct UIDs such as API_Latency and api-latency share one
+func checkKubernetesNames(slos []*spec.SLO) error {
+	var errs []error
+	seen := map[string]*spec.SLO{}
+
+	for _, s := range slos {
+		name := kubernetes.Name(s.UID)
+		other, ok := seen[name]
+		switch {
+		case !ok:
+			seen[name] = s
+		case other.UID != s.UID:
+			// Duplicate UIDs are left to spec.CheckUnique
+			errs = append(errs, fmt.Errorf("%s: uid %q has the same object name %q as uid %q in %s",
+				s.Source, s.UID, name, other.UID, other.Source))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// keyValues is a repeatable key=value flag
+type keyValues map[string]string
+
+func (kv *keyValues) String() string {
+	pairs := make([]string, 0, len(*kv))
+	for _, k := range slices.Sorted(maps.Keys(*kv)) {
+		pairs = append(pairs, k+"="+(*kv)[k])
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (kv *keyValues) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("%q must be key=value", value)
+	}
+	if *kv == nil {
+		*kv = keyValues{}
+	}
+	(*kv)[key] = val
+	return nil
+}
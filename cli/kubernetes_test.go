@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const kubernetesSpec = `
+service: api
+slos:
+  - uid: %s
+    name: API availability
+    kind: availability
+    target: 0.999
+    window: 28d
+    metrics:
+      bad: http_requests_total{code=~"5.."}
+      total: http_requests_total
+`
+
+func TestGenerateKubernetesNameCollision(t *testing.T) {
+	specDir, outputDir := t.TempDir(), t.TempDir()
+	for file, uid := range map[string]string{"a.yaml": "API_Availability", "b.yaml": "api-availability", "c.yaml": "web"} {
+		content := fmt.Sprintf(kubernetesSpec, uid)
+		if err := os.WriteFile(filepath.Join(specDir, file), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{{}, {"-slo", "web"}} {
+		var stdout, stderr bytes.Buffer
+		args = append([]string{"-specs", specDir, "-out", outputDir, "-format", "kubernetes"}, args...)
+		code := runGenerate(args, &stdout, &stderr)
+
+		want := `b.yaml: uid "api-availability" has the same object name "api-availability" as uid "API_Availability"`
+		if code == ExitOK || !strings.Contains(stderr.String(), want) {
+			t.Errorf("%v: exit code %d, want the collision reported\n%s", args, code, stderr.String())
+		}
+		if entries, _ := os.ReadDir(outputDir); len(entries) != 0 {
+			t.Errorf("%v: wrote %d file(s), want none", args, len(entries))
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := runGenerate([]string{"-specs", specDir, "-out", outputDir}, &stdout, &stderr)
+	if code != ExitOK {
+		t.Errorf("files format: exit code %d, want %d: object names only matter to kubernetes\n%s", code, ExitOK, stderr.String())
+	}
+}
@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+func runList(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet("list", stderr, opts)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	r := &report{}
+	slos, err := opts.load(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "UID\tKIND\tSERVICE\tOPERATION\tTARGET\tWINDOW\tOWNER")
+	for _, s := range slos {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%g\t%s\t%s\n", s.UID, s.Kind, s.Service, s.Operation, s.Target, s.Window, s.Owner)
+	}
+	tw.Flush()
+
+	return r.print(stderr)
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+// The provider config and the dashboards directory of the provisioning format
+const (
+	provisioningConfigPath = "provisioning/dashboards/dashboards.yaml"
+	provisioningDashboards = "dashboards"
+	unownedTeam            = "unowned"
+)
+
+var unsafePathChars = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// dashboardPath is where the dashboard of an SLO is written, relative to the output directory
+func (o *options) dashboardPath(s *spec.SLO) string {
+	if o.format != formatProvisioning {
+		return s.UID + ".json"
+	}
+	return filepath.Join(provisioningDashboards, filepath.FromSlash(folderDir(s)), s.UID+".json")
+}
+
+// treeArtifacts are the files describing the whole output directory rather than a single SLO
+func (o *options) treeArtifacts(slos []*spec.SLO) ([]artifact, error) {
+	switch o.format {
+	case formatTerraform:
+		return terraformFolders(slos)
+	case formatProvisioning:
+	default:
+		return nil, nil
+	}
+
+	// One provider per team/service directory, so each gets its own folder
+	var providers []grafana.Provider
+	seen := map[string]bool{}
+	for _, s := range slos {
+		dir := folderDir(s)
+		if seen[dir] {
+			continue
+		}
+		seen[dir] = true
+		providers = append(providers, grafana.NewFileProvider(
+			strings.ReplaceAll(dir, "/", "-"),
+			folderTitle(s),
+			path.Join(o.provisioningPath, provisioningDashboards, dir),
+		))
+	}
+	slices.SortFunc(providers, func(a, b grafana.Provider) int { return strings.Compare(a.Name, b.Name) })
+
+	config, err := grafana.NewProvisioningConfig(providers...).ToYAML()
+	if err != nil {
+		return nil, fmt.Errorf("building provisioning config: %w", err)
+	}
+	return []artifact{{path: filepath.FromSlash(provisioningConfigPath), content: config}}, nil
+}
+
+// folderDir is the team/service directory of an SLO, one per dashboard folder
+func folderDir(s *spec.SLO) string {
+	return path.Join(teamDir(s), pathSegment(s.Service))
+}
+
+func teamDir(s *spec.SLO) string {
+	if s.Owner == "" {
+		return unownedTeam
+	}
+	return pathSegment(s.Owner)
+}
+
+func folderTitle(s *spec.SLO) string {
+	if s.Owner == "" {
+		return s.Service
+	}
+	return s.Owner + " / " + s.Service
+}
+
+// pathSegment turns a name into a lowercase directory name
+func pathSegment(name string) string {
+	return strings.Trim(unsafePathChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// stdin answers the confirmation prompt of prune
+var stdin io.Reader = os.Stdin
+
+func runPrune(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet("prune", stderr, opts)
+	opts.addBuildFlags(fs)
+	opts.addGrafanaFlags(fs)
+	remove := fs.Bool("delete", false, "delete the stale dashboards instead of only listing them")
+	yes := fs.Bool("yes", false, "delete without asking for confirmation")
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	// Every SLO has to be known, or its dashboard would look stale
+	if opts.selected != "" {
+		fmt.Fprintln(stderr, "error: prune always considers every SLO, -slo can't be used")
+		return ExitUsage
+	}
+
+	client, err := opts.grafanaClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	r := &report{}
+	slos, err := opts.load(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+	if r.failed() {
+		fmt.Fprintln(stderr, "not pruning: some spec files could not be loaded, their dashboards would look stale")
+		return r.print(stderr)
+	}
+
+	keep := map[string]bool{}
+	for _, s := range slos {
+		keep[s.UID] = true
+	}
+
+	ctx := context.Background()
+	stale, err := client.StaleDashboards(ctx, opts.folder, slo.ManagedTag, keep)
+	if err != nil {
+		r.fail("", fmt.Errorf("listing dashboards: %w", err))
+		return r.print(stderr)
+	}
+
+	for _, d := range stale {
+		fmt.Fprintf(stdout, "stale: %s (%s)\n", d.UID, d.Title)
+	}
+	fmt.Fprintf(stdout, "%d stale dashboard(s) in folder %q\n", len(stale), opts.folder)
+
+	if len(stale) == 0 || !*remove {
+		return ExitOK
+	}
+	if !*yes && !confirm(stdout, fmt.Sprintf("Delete %d dashboard(s) from %s?", len(stale), client.URL)) {
+		fmt.Fprintln(stdout, "nothing deleted")
+		return ExitOK
+	}
+
+	deleted := 0
+	for _, d := range stale {
+		if err := client.DeleteDashboard(ctx, d.UID); err != nil {
+			r.fail(d.UID, err)
+			continue
+		}
+		fmt.Fprintf(stdout, "deleted %s\n", d.UID)
+		deleted++
+	}
+	fmt.Fprintf(stdout, "deleted %d dashboard(s)\n", deleted)
+	return r.print(stderr)
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no
+func confirm(stdout io.Writer, question string) bool {
+	fmt.Fprintf(stdout, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(stdin).ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+	return false
+}
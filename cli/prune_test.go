@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/internal/grafanatest"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+const pruneSpec = `
+service: api
+slos:
+  - uid: kept
+    name: API availability
+    kind: availability
+    target: 0.999
+    window: 28d
+    metrics:
+      bad: http_requests_total{code=~"5.."}
+      total: http_requests_total
+`
+
+// newPruneServer holds the dashboard of the only SLO of pruneSpec, a stale one and an unmanaged one
+func newPruneServer(t *testing.T) (*grafanatest.Server, string) {
+	fake := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"})
+	fake.AddDashboard("kept", "API availability", "slos", slo.ManagedTag)
+	fake.AddDashboard("stale", "Removed SLO", "slos", slo.ManagedTag)
+	fake.AddDashboard("handmade", "Handmade", "slos")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "api.yaml"), []byte(pruneSpec), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return fake, dir
+}
+
+func runPruneWith(t *testing.T, fake *grafanatest.Server, specDir, answer string, args ...string) (int, string) {
+	t.Helper()
+	previous := stdin
+	stdin = strings.NewReader(answer)
+	t.Cleanup(func() { stdin = previous })
+
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-specs", specDir, "-grafana-url", fake.URL, "-grafana-token", grafanatest.Token}, args...)
+	code := runPrune(args, &stdout, &stderr)
+	return code, stdout.String() + stderr.String()
+}
+
+func TestPruneDeletesStaleDashboards(t *testing.T) {
+	fake, specDir := newPruneServer(t)
+
+	code, output := runPruneWith(t, fake, specDir, "y\n", "-delete")
+	if code != ExitOK {
+		t.Fatalf("exit code %d, want %d\n%s", code, ExitOK, output)
+	}
+	if got := fake.Deleted(); !slices.Equal(got, []string{"stale"}) {
+		t.Errorf("deleted %q, want only the stale dashboard\n%s", got, output)
+	}
+}
+
+func TestPruneListsWithoutDelete(t *testing.T) {
+	fake, specDir := newPruneServer(t)
+
+	code, output := runPruneWith(t, fake, specDir, "y\n")
+	if code != ExitOK || !strings.Contains(output, "stale: stale (Removed SLO)") {
+		t.Errorf("exit code %d, want %d and the stale dashboard listed\n%s", code, ExitOK, output)
+	}
+	if got := fake.Deleted(); len(got) != 0 {
+		t.Errorf("deleted %q without -delete", got)
+	}
+}
+
+func TestPruneDeclined(t *testing.T) {
+	for _, answer := range []string{"n\n", "\n", "", "maybe\n"} {
+		fake, specDir := newPruneServer(t)
+
+		code, output := runPruneWith(t, fake, specDir, answer, "-delete")
+		if code != ExitOK || !strings.Contains(output, "nothing deleted") {
+			t.Errorf("answer %q: exit code %d, want %d and nothing deleted\n%s", answer, code, ExitOK, output)
+		}
+		if got := fake.Deleted(); len(got) != 0 {
+			t.Errorf("answer %q: deleted %q", answer, got)
+		}
+	}
+}
+
+func TestPruneSearchFailure(t *testing.T) {
+	fake, specDir := newPruneServer(t)
+	fake.SearchStatus = http.StatusInternalServerError
+
+	code, output := runPruneWith(t, fake, specDir, "y\n", "-delete", "-yes")
+	if code == ExitOK {
+		t.Errorf("exit code %d, want a failure\n%s", code, output)
+	}
+	if got := fake.Deleted(); len(got) != 0 {
+		t.Errorf("deleted %q after a failed search", got)
+	}
+}
+
+func TestPruneUnloadableSpecs(t *testing.T) {
+	fake, specDir := newPruneServer(t)
+	if err := os.WriteFile(filepath.Join(specDir, "broken.yaml"), []byte("slos: ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	code, output := runPruneWith(t, fake, specDir, "y\n", "-delete", "-yes")
+	if code == ExitOK || !strings.Contains(output, "not pruning") {
+		t.Errorf("exit code %d, want a failure before pruning\n%s", code, output)
+	}
+	if got := fake.Deleted(); len(got) != 0 {
+		t.Errorf("deleted %q although a spec file could not be loaded", got)
+	}
+}
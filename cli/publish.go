@@ -0,0 +1,95 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/spec"
+)
+
+func runPublish(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet("publish", stderr, opts)
+	opts.addBuildFlags(fs)
+	opts.addGrafanaFlags(fs)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	client, err := opts.grafanaClient()
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	dashboards := buildDashboards(slos, r)
+	published := publish(stdout, r, grafana.NewPublisher(client, opts.folder), dashboards)
+
+	fmt.Fprintf(stdout, "published %d dashboard(s) to %s\n", published, client.URL)
+	return r.print(stderr)
+}
+
+// buildDashboards generates the dashboard of every SLO, recording the ones that fail to build
+func buildDashboards(slos []*spec.SLO, r *report) []grafana.Dashboard {
+	var dashboards []grafana.Dashboard
+	for _, s := range slos {
+		d, err := buildDashboard(s)
+		if err != nil {
+			r.fail(s.UID, err)
+			continue
+		}
+		dashboards = append(dashboards, d)
+	}
+	return dashboards
+}
+
+// publish uploads the dashboards, printing each one, and returns how many were published
+func publish(stdout io.Writer, r *report, publisher *grafana.Publisher, dashboards []grafana.Dashboard) int {
+	if len(dashboards) == 0 {
+		return 0
+	}
+
+	published := 0
+	for _, result := range publisher.Publish(context.Background(), dashboards) {
+		if result.Err != nil {
+			r.fail(result.UID, result.Err)
+			continue
+		}
+		fmt.Fprintf(stdout, "published %s (version %d) %s\n", result.UID, result.Version, result.URL)
+		published++
+	}
+	return published
+}
+
+// addGrafanaFlags registers the flags of commands that talk to the Grafana API
+func (o *options) addGrafanaFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.grafanaToken, "grafana-token", "", "Grafana service account token (default $GRAFANA_TOKEN)")
+	fs.StringVar(&o.folder, "folder", "SLOs", "Grafana folder the dashboards are published to, created if missing")
+	fs.DurationVar(&o.timeout, "timeout", 30*time.Second, "timeout of each Grafana API request")
+}
+
+// grafanaClient checks the Grafana flags and returns a client for the API
+func (o *options) grafanaClient() (*grafana.Client, error) {
+	if o.grafanaURL == "" {
+		return nil, fmt.Errorf("-grafana-url is required")
+	}
+
+	token := o.grafanaToken
+	if token == "" {
+		token = os.Getenv("GRAFANA_TOKEN")
+	}
+	return grafana.NewClient(o.grafanaURL, token).WithHTTPClient(&http.Client{Timeout: o.timeout}), nil
+}
@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// report collects failures so that a broken SLO doesn't hide problems with the others
+type report struct {
+	failures []failure
+}
+
+type failure struct {
+	uid string
+	err error
+}
+
+func (r *report) fail(uid string, err error) {
+	r.failures = append(r.failures, failure{uid: uid, err: err})
+}
+
+// failAll records every error wrapped in err separately
+func (r *report) failAll(uid string, err error) {
+	if err == nil {
+		return
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			r.fail(uid, e)
+		}
+		return
+	}
+	r.fail(uid, err)
+}
+
+func (r *report) failed() bool {
+	return len(r.failures) > 0
+}
+
+// print writes the failure summary and returns the matching exit code
+func (r *report) print(w io.Writer) int {
+	if !r.failed() {
+		return ExitOK
+	}
+
+	fmt.Fprintf(w, "\n%d failure(s):\n", len(r.failures))
+	for _, f := range r.failures {
+		message := strings.ReplaceAll(f.err.Error(), "\n", "\n    ")
+		if f.uid == "" {
+			fmt.Fprintf(w, "  - %s\n", message)
+			continue
+		}
+		fmt.Fprintf(w, "  - %s: %s\n", f.uid, message)
+	}
+	return ExitFailure
+}
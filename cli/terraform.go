@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/rules"
+	"unobravo.com/go-obs-as-code/slo"
+	"unobravo.com/go-obs-as-code/spec"
+	"unobravo.com/go-obs-as-code/terraform"
+)
+
+// The directory and the shared folders file of the terraform format
+const (
+	terraformDir         = "terraform"
+	terraformFoldersFile = "folders.tf.json"
+)
+
+// terraformArtifacts declares the dashboard and the Grafana-managed alerts of an SLO.
+// Recording rules stay in a Prometheus rule file, as Grafana can't evaluate them.
+func terraformArtifacts(s *spec.SLO, opts *options, generator slo.SLO, dashboardJSON string) ([]artifact, error) {
+	group, err := opts.grafanaAlertGroup(s, generator)
+	if err != nil {
+		return nil, err
+	}
+	folder := folderResource(s)
+	config, err := terraform.NewConfig().
+		WithDashboard(s.UID, folder, dashboardJSON).
+		WithRuleGroup(s.UID, folder, group)
+	if err != nil {
+		return nil, fmt.Errorf("building alert rules: %w", err)
+	}
+	configJSON, err := config.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("building terraform config: %w", err)
+	}
+
+	rulesYAML, err := rules.NewFile(rules.RecordingGroup(generator)).ToYAML()
+	if err != nil {
+		return nil, fmt.Errorf("building rules: %w", err)
+	}
+
+	return []artifact{
+		{path: filepath.Join(terraformDir, s.UID+".tf.json"), content: configJSON},
+		{path: filepath.Join("rules", s.UID+".yaml"), content: rulesYAML},
+	}, nil
+}
+
+// terraformFolders declares the folder of every team/service the SLOs belong to
+func terraformFolders(slos []*spec.SLO) ([]artifact, error) {
+	config := terraform.NewConfig()
+	for _, s := range slos {
+		config.WithFolder(folderResource(s), folderTitle(s))
+	}
+
+	configJSON, err := config.ToJSON()
+	if err != nil {
+		return nil, fmt.Errorf("building terraform folders: %w", err)
+	}
+	return []artifact{{path: filepath.Join(terraformDir, terraformFoldersFile), content: configJSON}}, nil
+}
+
+// folderResource is the resource name of the folder of an SLO, stable as long as its owner and service are
+func folderResource(s *spec.SLO) string {
+	return terraform.ResourceName("slo_" + strings.ReplaceAll(folderDir(s), "/", "_"))
+}
@@ -0,0 +1,34 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+func runValidate(args []string, stdout, stderr io.Writer) int {
+	opts := &options{}
+	fs := newFlagSet("validate", stderr, opts)
+	opts.addBuildFlags(fs)
+	if code, stop := parseFlags(fs, args); stop {
+		return code
+	}
+
+	r := &report{}
+	slos, err := opts.loadValid(r)
+	if err != nil {
+		fmt.Fprintf(stderr, "error: %v\n", err)
+		return ExitUsage
+	}
+
+	valid := 0
+	for _, s := range slos {
+		if _, err := buildArtifacts(s, opts); err != nil {
+			r.fail(s.UID, err)
+			continue
+		}
+		valid++
+	}
+
+	fmt.Fprintf(stdout, "%d SLO(s) valid\n", valid)
+	return r.print(stderr)
+}
@@ -0,0 +1,181 @@
+package components
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+)
+
+// GridColumns is the width of a Grafana dashboard, in grid columns
+const GridColumns = 24
+
+// Width is a panel's share of the dashboard's width
+type Width float64
+
+const (
+	Full    Width = 1
+	Half    Width = 1.0 / 2
+	Third   Width = 1.0 / 3
+	Quarter Width = 1.0 / 4
+)
+
+// Columns is the width of n grid columns
+func Columns(n uint32) Width {
+	return Width(float64(n) / GridColumns)
+}
+
+// columns rounds the width to whole grid columns, between 1 and the whole grid
+func (w Width) columns() uint32 {
+	return uint32(min(max(math.Round(float64(w)*GridColumns), 1), GridColumns))
+}
+
+// Placeable is a panel whose position a Layout can set
+type Placeable interface {
+	Panel
+	Place(pos dashboard.GridPos)
+}
+
+// Layout stacks rows of panels, computing every position from the panels' widths and heights
+type Layout struct {
+	rows []*Row
+}
+
+func NewLayout() *Layout {
+	return &Layout{}
+}
+
+// Row appends a row. An untitled row has no header: its panels follow the previous ones directly.
+func (l *Layout) Row(title string) *Row {
+	row := &Row{Title: title, Height: DefaultPanelHeight}
+	l.rows = append(l.rows, row)
+	return row
+}
+
+// DefaultPanelHeight is the height of the panels of a row that doesn't set one, in grid units
+const DefaultPanelHeight = 8
+
+// Row is a band of panels, laid out left to right and wrapped onto a new line past the last column
+type Row struct {
+	Title string
+
+	// Collapsed rows show only their header until expanded
+	Collapsed bool
+
+	// Height is the height of the row's panels that don't set their own
+	Height uint32
+
+	// Y is the top of the row's header, or of its first panel when untitled, once the layout is arranged
+	Y uint32
+
+	items []*rowItem
+}
+
+type rowItem struct {
+	panel  Placeable
+	width  Width
+	height uint32
+	pos    *dashboard.GridPos
+}
+
+// Collapse makes the row start collapsed
+func (r *Row) Collapse() *Row {
+	r.Collapsed = true
+	return r
+}
+
+// WithHeight sets the height of the row's panels that don't set their own
+func (r *Row) WithHeight(height uint32) *Row {
+	r.Height = height
+	return r
+}
+
+// WithPanel places a panel after the previous one, at the row's height
+func (r *Row) WithPanel(panel Placeable, width Width) *Row {
+	return r.WithSizedPanel(panel, width, 0)
+}
+
+// WithSizedPanel places a panel after the previous one, with its own height
+func (r *Row) WithSizedPanel(panel Placeable, width Width, height uint32) *Row {
+	r.items = append(r.items, &rowItem{panel: panel, width: width, height: height})
+	return r
+}
+
+// WithPanelAt keeps a panel at an explicit position, whose Y is relative to the top of the row's panels.
+// The other panels don't flow around it.
+func (r *Row) WithPanelAt(panel Placeable, pos dashboard.GridPos) *Row {
+	r.items = append(r.items, &rowItem{panel: panel, pos: &pos})
+	return r
+}
+
+// Panels are the row's panels, in the order they were added, leaving out nil ones
+func (r *Row) Panels() []Panel {
+	panels := make([]Panel, 0, len(r.items))
+	for _, item := range r.items {
+		if !IsNil(item.panel) {
+			panels = append(panels, item.panel)
+		}
+	}
+	return panels
+}
+
+// Build is the header of a titled row
+func (r *Row) Build() *dashboard.RowBuilder {
+	return dashboard.NewRowBuilder(r.Title).
+		Collapsed(r.Collapsed).
+		GridPos(dashboard.GridPos{H: 1, W: GridColumns, X: 0, Y: r.Y})
+}
+
+// Arrange positions every panel and returns the rows in order.
+// Nil panels are reported and left out.
+func (l *Layout) Arrange() ([]*Row, error) {
+	var errs []error
+	var y uint32
+	for i, row := range l.rows {
+		row.Y = y
+		top := y
+		if row.Title != "" {
+			top++
+		}
+
+		var x, lineHeight, bottom uint32
+		lineTop := top
+		bottom = top
+		for j, item := range row.items {
+			if IsNil(item.panel) {
+				errs = append(errs, fmt.Errorf("row #%d %q: panel #%d: nil panel", i+1, row.Title, j+1))
+				continue
+			}
+
+			if item.pos != nil {
+				pos := *item.pos
+				pos.Y += top
+				item.panel.Place(pos)
+				bottom = max(bottom, pos.Y+pos.H)
+				continue
+			}
+
+			width := item.width.columns()
+			height := item.height
+			if height == 0 {
+				height = row.Height
+			}
+			if x+width > GridColumns {
+				x, lineTop, lineHeight = 0, lineTop+lineHeight, 0
+			}
+
+			item.panel.Place(dashboard.GridPos{H: height, W: width, X: x, Y: lineTop})
+			x += width
+			lineHeight = max(lineHeight, height)
+			bottom = max(bottom, lineTop+lineHeight)
+		}
+		// A collapsed row only takes its header's height, its panels are shown below it once expanded
+		if row.Title != "" && row.Collapsed {
+			y = top
+		} else {
+			y = bottom
+		}
+	}
+	return l.rows, errors.Join(errs...)
+}
@@ -0,0 +1,165 @@
+package components
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+)
+
+func panel(title string) *TextPanel {
+	return NewTextPanel(title, "", dashboard.GridPos{})
+}
+
+func checkPos(t *testing.T, p *TextPanel, want dashboard.GridPos) {
+	t.Helper()
+	if p.GridPos != want {
+		t.Errorf("%s at %+v, want %+v", p.Title, p.GridPos, want)
+	}
+}
+
+func TestArrangeWrapsPastLastColumn(t *testing.T) {
+	a, b, c, d := panel("a"), panel("b"), panel("c"), panel("d")
+	layout := NewLayout()
+	layout.Row("").WithPanel(a, Half).WithPanel(b, Third).WithPanel(c, Third).WithPanel(d, Full)
+
+	if _, err := layout.Arrange(); err != nil {
+		t.Fatal(err)
+	}
+	checkPos(t, a, dashboard.GridPos{X: 0, Y: 0, W: 12, H: DefaultPanelHeight})
+	checkPos(t, b, dashboard.GridPos{X: 12, Y: 0, W: 8, H: DefaultPanelHeight})
+	checkPos(t, c, dashboard.GridPos{X: 0, Y: 8, W: 8, H: DefaultPanelHeight})
+	checkPos(t, d, dashboard.GridPos{X: 0, Y: 16, W: 24, H: DefaultPanelHeight})
+}
+
+func TestArrangeColumns(t *testing.T) {
+	tests := []struct {
+		width Width
+		want  uint32
+	}{
+		{Full, 24},
+		{Half, 12},
+		{Third, 8},
+		{Quarter, 6},
+		{Columns(5), 5},
+		{0, 1},
+		{2, 24},
+	}
+
+	for _, tt := range tests {
+		p := panel("p")
+		layout := NewLayout()
+		layout.Row("").WithPanel(p, tt.width)
+		if _, err := layout.Arrange(); err != nil {
+			t.Fatal(err)
+		}
+		if p.GridPos.W != tt.want {
+			t.Errorf("width %g: %d columns, want %d", tt.width, p.GridPos.W, tt.want)
+		}
+	}
+}
+
+func TestArrangeLineHeightFromTallestPanel(t *testing.T) {
+	short, tall, next, below := panel("short"), panel("tall"), panel("next"), panel("below")
+	layout := NewLayout()
+	layout.Row("").WithHeight(4).
+		WithPanel(short, Half).
+		WithSizedPanel(tall, Half, 10).
+		WithPanel(next, Full)
+	layout.Row("Below").WithPanel(below, Full)
+
+	rows, err := layout.Arrange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPos(t, short, dashboard.GridPos{X: 0, Y: 0, W: 12, H: 4})
+	checkPos(t, tall, dashboard.GridPos{X: 12, Y: 0, W: 12, H: 10})
+	checkPos(t, next, dashboard.GridPos{X: 0, Y: 10, W: 24, H: 4})
+	if rows[1].Y != 14 {
+		t.Errorf("next row at %d, want 14, under the tallest panel", rows[1].Y)
+	}
+	checkPos(t, below, dashboard.GridPos{X: 0, Y: 15, W: 24, H: DefaultPanelHeight})
+}
+
+func TestArrangeRowHeaders(t *testing.T) {
+	first, second := panel("first"), panel("second")
+	layout := NewLayout()
+	layout.Row("First").WithPanel(first, Full)
+	layout.Row("").WithPanel(second, Full)
+
+	rows, err := layout.Arrange()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows[0].Y != 0 || rows[1].Y != 9 {
+		t.Errorf("rows at %d and %d, want 0 and 9", rows[0].Y, rows[1].Y)
+	}
+	checkPos(t, first, dashboard.GridPos{X: 0, Y: 1, W: 24, H: DefaultPanelHeight})
+	checkPos(t, second, dashboard.GridPos{X: 0, Y: 9, W: 24, H: DefaultPanelHeight})
+}
+
+func TestArrangeCollapsedRow(t *testing.T) {
+	nested, more, after := panel("nested"), panel("more"), panel("after")
+	layout := NewLayout()
+	layout.Row("Details").Collapse().WithPanel(nested, Full).WithPanel(more, Full)
+	layout.Row("After").WithPanel(after, Full)
+
+	rows, err := layout.Arrange()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The nested panels sit under the header, where Grafana shows them once expanded
+	checkPos(t, nested, dashboard.GridPos{X: 0, Y: 1, W: 24, H: DefaultPanelHeight})
+	checkPos(t, more, dashboard.GridPos{X: 0, Y: 9, W: 24, H: DefaultPanelHeight})
+	if got := rows[0].Panels(); len(got) != 2 || got[0] != Panel(nested) || got[1] != Panel(more) {
+		t.Errorf("collapsed row holds %v, want its two panels", got)
+	}
+
+	// The next row follows the collapsed header directly
+	if rows[1].Y != 1 {
+		t.Errorf("row after a collapsed one at %d, want 1", rows[1].Y)
+	}
+	checkPos(t, after, dashboard.GridPos{X: 0, Y: 2, W: 24, H: DefaultPanelHeight})
+}
+
+func TestArrangePanelAt(t *testing.T) {
+	fixed, flowing, after := panel("fixed"), panel("flowing"), panel("after")
+	layout := NewLayout()
+	layout.Row("Row").
+		WithPanelAt(fixed, dashboard.GridPos{X: 20, Y: 2, W: 4, H: 12}).
+		WithPanel(flowing, Half)
+	layout.Row("After").WithPanel(after, Full)
+
+	rows, err := layout.Arrange()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Y is relative to the top of the row's panels, and the other panels don't flow around it
+	checkPos(t, fixed, dashboard.GridPos{X: 20, Y: 3, W: 4, H: 12})
+	checkPos(t, flowing, dashboard.GridPos{X: 0, Y: 1, W: 12, H: DefaultPanelHeight})
+
+	// The row still ends below it
+	if rows[1].Y != 15 {
+		t.Errorf("next row at %d, want 15, below the explicitly placed panel", rows[1].Y)
+	}
+	checkPos(t, after, dashboard.GridPos{X: 0, Y: 16, W: 24, H: DefaultPanelHeight})
+}
+
+func TestArrangeNilPanels(t *testing.T) {
+	var missing *TextPanel
+	kept := panel("kept")
+	layout := NewLayout()
+	layout.Row("Row").WithPanel(missing, Half).WithPanel(nil, Half).WithPanel(kept, Half)
+
+	rows, err := layout.Arrange()
+	if err == nil || !strings.Contains(err.Error(), `row #1 "Row": panel #1: nil panel`) ||
+		!strings.Contains(err.Error(), `row #1 "Row": panel #2: nil panel`) {
+		t.Errorf("got error %v, want both nil panels reported", err)
+	}
+	checkPos(t, kept, dashboard.GridPos{X: 0, Y: 1, W: 12, H: DefaultPanelHeight})
+	if got := rows[0].Panels(); len(got) != 1 || got[0] != Panel(kept) {
+		t.Errorf("row holds %v, want only the non-nil panel", got)
+	}
+}
@@ -0,0 +1,34 @@
+package components
+
+import (
+	"reflect"
+
+	"github.com/grafana/grafana-foundation-sdk/go/cog"
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+)
+
+// Panel is implemented by every panel a dashboard can hold.
+// Panel types defined outside this package only need to provide their builder.
+type Panel interface {
+	PanelBuilder() cog.Builder[dashboard.Panel]
+}
+
+// IsNil reports whether a panel, or any value held in an interface, is nil.
+// It catches nil pointers wrapped in the interface, which == nil doesn't.
+func IsNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Func:
+		return rv.IsNil()
+	}
+	return false
+}
+
+var (
+	_ Placeable = (*StatPanel)(nil)
+	_ Placeable = (*TimeSeriesPanel)(nil)
+	_ Placeable = (*TextPanel)(nil)
+)
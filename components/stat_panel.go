@@ -1,12 +1,14 @@
 package components
 
 import (
+	"github.com/grafana/grafana-foundation-sdk/go/cog"
 	"github.com/grafana/grafana-foundation-sdk/go/common"
 	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
 	"github.com/grafana/grafana-foundation-sdk/go/stat"
 )
 
 type StatPanel struct {
+	ID              uint32
 	Title           string
 	Description     string
 	GridPos         dashboard.GridPos
@@ -49,6 +51,12 @@ func NewStatPanel(title, description string, gridPos dashboard.GridPos) *StatPan
 	}
 }
 
+// WithID fixes the panel ID, which Grafana otherwise assigns on save
+func (p *StatPanel) WithID(id uint32) *StatPanel {
+	p.ID = id
+	return p
+}
+
 func (p *StatPanel) WithDatasource(ds *DatasourceConfig) *StatPanel {
 	p.Datasource = ds
 	return p
@@ -93,6 +101,10 @@ func (p *StatPanel) Build() *stat.PanelBuilder {
 		Transparent(p.Transparent).
 		GridPos(p.GridPos)
 
+	if p.ID != 0 {
+		builder = builder.Id(p.ID)
+	}
+
 	if p.Datasource != nil {
 		builder = builder.Datasource(dashboard.DataSourceRef{
 			Type: &p.Datasource.Type,
@@ -146,3 +158,12 @@ func (p *StatPanel) Build() *stat.PanelBuilder {
 
 	return builder
 }
+
+func (p *StatPanel) PanelBuilder() cog.Builder[dashboard.Panel] {
+	return p.Build()
+}
+
+// Place moves the panel, as a Layout does
+func (p *StatPanel) Place(pos dashboard.GridPos) {
+	p.GridPos = pos
+}
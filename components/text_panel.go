@@ -1,6 +1,7 @@
 package components
 
 import (
+	"github.com/grafana/grafana-foundation-sdk/go/cog"
 	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
 	"github.com/grafana/grafana-foundation-sdk/go/text"
 )
@@ -28,3 +29,12 @@ func (p *TextPanel) Build() *text.PanelBuilder {
 		Transparent(p.Transparent).
 		GridPos(p.GridPos)
 }
+
+func (p *TextPanel) PanelBuilder() cog.Builder[dashboard.Panel] {
+	return p.Build()
+}
+
+// Place moves the panel, as a Layout does
+func (p *TextPanel) Place(pos dashboard.GridPos) {
+	p.GridPos = pos
+}
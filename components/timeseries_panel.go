@@ -1,6 +1,7 @@
 package components
 
 import (
+	"github.com/grafana/grafana-foundation-sdk/go/cog"
 	"github.com/grafana/grafana-foundation-sdk/go/common"
 	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
 	"github.com/grafana/grafana-foundation-sdk/go/timeseries"
@@ -108,3 +109,12 @@ func (p *TimeSeriesPanel) Build() *timeseries.PanelBuilder {
 
 	return builder
 }
+
+func (p *TimeSeriesPanel) PanelBuilder() cog.Builder[dashboard.Panel] {
+	return p.Build()
+}
+
+// Place moves the panel, as a Layout does
+func (p *TimeSeriesPanel) Place(pos dashboard.GridPos) {
+	p.GridPos = pos
+}
@@ -0,0 +1,134 @@
+package grafana
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+
+	"unobravo.com/go-obs-as-code/internal/yamlfile"
+)
+
+// Annotations Grafana reads to link an alert to a dashboard panel
+const (
+	DashboardUIDAnnotation = "__dashboardUid__"
+	PanelIDAnnotation      = "__panelId__"
+)
+
+// ExpressionDatasource is the UID Grafana reserves for server-side expressions
+const ExpressionDatasource = "__expr__"
+
+// AlertingProvisioning is an alert rule provisioning file, as read from provisioning/alerting
+// and produced by Grafana's alert rule export
+type AlertingProvisioning struct {
+	APIVersion int              `yaml:"apiVersion"`
+	Groups     []AlertRuleGroup `yaml:"groups"`
+}
+
+// AlertRuleGroup is a set of Grafana-managed alert rules evaluated together
+type AlertRuleGroup struct {
+	OrgID    int         `yaml:"orgId"`
+	Name     string      `yaml:"name"`
+	Folder   string      `yaml:"folder"`
+	Interval string      `yaml:"interval"`
+	Rules    []AlertRule `yaml:"rules"`
+}
+
+// AlertRule fires when the node named by Condition is non-zero
+type AlertRule struct {
+	UID          string            `yaml:"uid"`
+	Title        string            `yaml:"title"`
+	Condition    string            `yaml:"condition"`
+	Data         []AlertQuery      `yaml:"data"`
+	NoDataState  string            `yaml:"noDataState"`
+	ExecErrState string            `yaml:"execErrState"`
+	For          string            `yaml:"for,omitempty"`
+	Annotations  map[string]string `yaml:"annotations,omitempty"`
+	Labels       map[string]string `yaml:"labels,omitempty"`
+	IsPaused     bool              `yaml:"isPaused"`
+}
+
+// AlertQuery is one node of a rule: a datasource query or a server-side expression
+type AlertQuery struct {
+	RefID             string            `yaml:"refId"`
+	DatasourceUID     string            `yaml:"datasourceUid"`
+	RelativeTimeRange RelativeTimeRange `yaml:"relativeTimeRange"`
+	Model             map[string]any    `yaml:"model"`
+}
+
+// RelativeTimeRange is in seconds before the evaluation time
+type RelativeTimeRange struct {
+	From int `yaml:"from"`
+	To   int `yaml:"to"`
+}
+
+func NewAlertingProvisioning(groups ...AlertRuleGroup) *AlertingProvisioning {
+	return &AlertingProvisioning{APIVersion: 1, Groups: groups}
+}
+
+func NewAlertRuleGroup(name, folder, interval string, rules ...AlertRule) AlertRuleGroup {
+	return AlertRuleGroup{OrgID: 1, Name: name, Folder: folder, Interval: interval, Rules: rules}
+}
+
+// NewPrometheusQuery is a query node evaluating expr at the evaluation time
+func NewPrometheusQuery(refID, datasourceUID, expr string, from int) AlertQuery {
+	return AlertQuery{
+		RefID:             refID,
+		DatasourceUID:     datasourceUID,
+		RelativeTimeRange: RelativeTimeRange{From: from},
+		Model: map[string]any{
+			"refId":   refID,
+			"expr":    expr,
+			"instant": true,
+			"range":   false,
+		},
+	}
+}
+
+// NewReduceExpression reduces every series of the input node to a single value with reducer, e.g. last
+func NewReduceExpression(refID, input, reducer string) AlertQuery {
+	return newExpression(refID, map[string]any{
+		"type":       "reduce",
+		"expression": input,
+		"reducer":    reducer,
+	})
+}
+
+// NewThresholdExpression is 1 where the input node is above threshold, and 0 elsewhere
+func NewThresholdExpression(refID, input string, threshold float64) AlertQuery {
+	return newExpression(refID, map[string]any{
+		"type":       "threshold",
+		"expression": input,
+		"conditions": []any{map[string]any{
+			"evaluator": map[string]any{"type": "gt", "params": []float64{threshold}},
+		}},
+	})
+}
+
+func newExpression(refID string, model map[string]any) AlertQuery {
+	model["refId"] = refID
+	model["datasource"] = map[string]any{"type": "__expr__", "uid": ExpressionDatasource}
+	return AlertQuery{RefID: refID, DatasourceUID: ExpressionDatasource, Model: model}
+}
+
+// Grafana rule UIDs are at most 40 characters long
+const maxRuleUIDLength = 40
+
+var invalidRuleUIDChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// AlertRuleUID derives a stable rule UID from a name, keeping it readable when it fits
+// and shortening it with a hash suffix otherwise
+func AlertRuleUID(name string) string {
+	uid := invalidRuleUIDChars.ReplaceAllString(name, "-")
+	if len(uid) <= maxRuleUIDLength {
+		return uid
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	suffix := fmt.Sprintf("-%08x", h.Sum32())
+	return uid[:maxRuleUIDLength-len(suffix)] + suffix
+}
+
+func (p *AlertingProvisioning) ToYAML() (string, error) {
+	return yamlfile.Encode(p)
+}
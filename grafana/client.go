@@ -0,0 +1,111 @@
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client talks to the Grafana HTTP API with a service account token
+type Client struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+func NewClient(url, token string) *Client {
+	return &Client{
+		URL:   strings.TrimSuffix(url, "/"),
+		Token: token,
+		HTTP:  http.DefaultClient,
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. to set a timeout or talk to a test server
+func (c *Client) WithHTTPClient(client *http.Client) *Client {
+	c.HTTP = client
+	return c
+}
+
+// Link turns a path returned by Grafana, which already includes the sub-path Grafana is served under,
+// into an absolute URL on the client's scheme and host
+func (c *Client) Link(path string) string {
+	base, err := url.Parse(c.URL)
+	if err != nil || base.Host == "" {
+		return c.URL + path
+	}
+	return (&url.URL{Scheme: base.Scheme, Host: base.Host}).String() + path
+}
+
+// APIError is a non-2xx response from Grafana
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s: %d %s", e.Method, e.Path, e.StatusCode, e.Message)
+}
+
+// do sends body as JSON and decodes the response into out, if not nil
+func (c *Client) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.URL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		apiErr := &APIError{Method: method, Path: path, StatusCode: resp.StatusCode}
+		var message struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &message) == nil && message.Message != "" {
+			apiErr.Message = message.Message
+		} else {
+			apiErr.Message = strings.TrimSpace(string(respBody))
+		}
+		return apiErr
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("%s %s: decoding response: %w", method, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+)
+
+// SaveResult is Grafana's answer to a saved dashboard
+type SaveResult struct {
+	UID     string `json:"uid"`
+	URL     string `json:"url"`
+	Status  string `json:"status"`
+	Version int    `json:"version"`
+}
+
+type saveRequest struct {
+	Dashboard json.RawMessage `json:"dashboard"`
+	FolderUID string          `json:"folderUid,omitempty"`
+	Message   string          `json:"message,omitempty"`
+	Overwrite bool            `json:"overwrite"`
+}
+
+// SaveDashboard creates the dashboard or overwrites the one with the same UID
+func (c *Client) SaveDashboard(ctx context.Context, dashboard json.RawMessage, folderUID, message string) (SaveResult, error) {
+	var result SaveResult
+	err := c.do(ctx, http.MethodPost, "/api/dashboards/db", saveRequest{
+		Dashboard: dashboard,
+		FolderUID: folderUID,
+		Message:   message,
+		Overwrite: true,
+	}, &result)
+	return result, err
+}
+
+// DeployedDashboard is the model of a dashboard saved in Grafana and the folder it is in
+type DeployedDashboard struct {
+	Model json.RawMessage
+
+	// FolderUID is empty for the General folder
+	FolderUID   string
+	FolderTitle string
+}
+
+// GetDashboard fetches the dashboard with the given UID; found is false if there is none
+func (c *Client) GetDashboard(ctx context.Context, uid string) (dashboard DeployedDashboard, found bool, err error) {
+	var response struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+		Meta      struct {
+			FolderUID   string `json:"folderUid"`
+			FolderTitle string `json:"folderTitle"`
+		} `json:"meta"`
+	}
+	err = c.do(ctx, http.MethodGet, "/api/dashboards/uid/"+url.PathEscape(uid), nil, &response)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+		return DeployedDashboard{}, false, nil
+	}
+	if err != nil {
+		return DeployedDashboard{}, false, err
+	}
+	return DeployedDashboard{
+		Model:       response.Dashboard,
+		FolderUID:   response.Meta.FolderUID,
+		FolderTitle: response.Meta.FolderTitle,
+	}, true, nil
+}
+
+// DashboardHit is a dashboard found by SearchDashboards
+type DashboardHit struct {
+	UID       string   `json:"uid"`
+	Title     string   `json:"title"`
+	Tags      []string `json:"tags"`
+	FolderUID string   `json:"folderUid"`
+}
+
+// SearchDashboards lists the dashboards carrying tag, limited to one folder unless folderUID is empty
+func (c *Client) SearchDashboards(ctx context.Context, tag, folderUID string) ([]DashboardHit, error) {
+	query := url.Values{"type": {"dash-db"}, "tag": {tag}, "limit": {"5000"}}
+	if folderUID != "" {
+		query.Set("folderUIDs", folderUID)
+	}
+
+	var hits []DashboardHit
+	if err := c.do(ctx, http.MethodGet, "/api/search?"+query.Encode(), nil, &hits); err != nil {
+		return nil, err
+	}
+	return hits, nil
+}
+
+// DeleteDashboard deletes the dashboard with the given UID
+func (c *Client) DeleteDashboard(ctx context.Context, uid string) error {
+	return c.do(ctx, http.MethodDelete, "/api/dashboards/uid/"+url.PathEscape(uid), nil, nil)
+}
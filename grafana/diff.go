@@ -0,0 +1,225 @@
+package grafana
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ChangeType tells whether something was added, removed or modified
+type ChangeType string
+
+const (
+	Added   ChangeType = "+"
+	Removed ChangeType = "-"
+	Changed ChangeType = "~"
+)
+
+// Change is one semantic difference between the deployed and the generated dashboard.
+// Target is e.g. `description`, `panel "SLI"`, `panel "SLI" query custom_sli` or `folder`;
+// Before and After hold the query expressions of a changed query, or the folder titles of a moved dashboard.
+type Change struct {
+	Type   ChangeType
+	Target string
+	Fields []string
+	Before string
+	After  string
+}
+
+func (c Change) String() string {
+	s := string(c.Type) + " " + c.Target
+	if len(c.Fields) > 0 {
+		s += ": " + strings.Join(c.Fields, ", ")
+	}
+	return s
+}
+
+// Volatile fields Grafana sets on save, which never count as a change
+var (
+	volatileDashboardFields = []string{"id", "version", "iteration"}
+	volatilePanelFields     = []string{"id", "pluginVersion"}
+)
+
+// Diff compares two dashboard models panel by panel and query by query,
+// ignoring the fields Grafana manages itself
+func Diff(deployed, desired []byte) ([]Change, error) {
+	var before, after map[string]any
+	if err := json.Unmarshal(deployed, &before); err != nil {
+		return nil, fmt.Errorf("decoding deployed dashboard: %w", err)
+	}
+	if err := json.Unmarshal(desired, &after); err != nil {
+		return nil, fmt.Errorf("decoding generated dashboard: %w", err)
+	}
+
+	for _, field := range volatileDashboardFields {
+		delete(before, field)
+		delete(after, field)
+	}
+	beforePanels, afterPanels := panelsByKey(before["panels"]), panelsByKey(after["panels"])
+	delete(before, "panels")
+	delete(after, "panels")
+
+	var changes []Change
+	changes = append(changes, diffFields("", before, after)...)
+
+	for _, p := range afterPanels {
+		old, ok := findPanel(beforePanels, p.key)
+		if !ok {
+			changes = append(changes, Change{Type: Added, Target: p.key})
+			continue
+		}
+		changes = append(changes, diffPanel(p.key, old.fields, p.fields)...)
+	}
+	for _, p := range beforePanels {
+		if _, ok := findPanel(afterPanels, p.key); !ok {
+			changes = append(changes, Change{Type: Removed, Target: p.key})
+		}
+	}
+	return changes, nil
+}
+
+// diffFields reports every top-level field that differs, as one change
+func diffFields(target string, before, after map[string]any) []Change {
+	var fields []string
+	for _, key := range unionKeys(before, after) {
+		if !reflect.DeepEqual(before[key], after[key]) {
+			fields = append(fields, key)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	if target == "" {
+		return []Change{{Type: Changed, Target: "dashboard", Fields: fields}}
+	}
+	return []Change{{Type: Changed, Target: target, Fields: fields}}
+}
+
+func diffPanel(key string, before, after map[string]any) []Change {
+	beforeTargets, afterTargets := targetsByRefID(before["targets"]), targetsByRefID(after["targets"])
+
+	before, after = without(before, "targets"), without(after, "targets")
+	for _, field := range volatilePanelFields {
+		delete(before, field)
+		delete(after, field)
+	}
+	changes := diffFields(key, before, after)
+
+	for _, refID := range unionKeys(beforeTargets, afterTargets) {
+		target := fmt.Sprintf("%s query %s", key, refID)
+		old, hadOld := beforeTargets[refID]
+		cur, hasCur := afterTargets[refID]
+		switch {
+		case !hadOld:
+			changes = append(changes, Change{Type: Added, Target: target, After: expr(cur)})
+		case !hasCur:
+			changes = append(changes, Change{Type: Removed, Target: target, Before: expr(old)})
+		case !reflect.DeepEqual(old, cur):
+			change := diffFields(target, old, cur)[0]
+			change.Before, change.After = expr(old), expr(cur)
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+type keyedPanel struct {
+	key    string
+	fields map[string]any
+}
+
+// panelsByKey flattens rows and names every panel after its title, or its type when untitled.
+// Repeated names are numbered in order.
+func panelsByKey(raw any) []keyedPanel {
+	var panels []keyedPanel
+	seen := map[string]int{}
+
+	var walk func(list any)
+	walk = func(list any) {
+		items, _ := list.([]any)
+		for _, item := range items {
+			panel, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			name, _ := panel["title"].(string)
+			if name == "" {
+				name, _ = panel["type"].(string)
+			}
+			kind := "panel"
+			if panel["type"] == "row" {
+				kind = "row"
+			}
+			key := fmt.Sprintf("%s %q", kind, name)
+			seen[key]++
+			if seen[key] > 1 {
+				key = fmt.Sprintf("%s #%d", key, seen[key])
+			}
+
+			// Collapsed rows hold their panels, which are compared on their own
+			nested := panel["panels"]
+			panels = append(panels, keyedPanel{key: key, fields: without(panel, "panels")})
+			walk(nested)
+		}
+	}
+	walk(raw)
+	return panels
+}
+
+func findPanel(panels []keyedPanel, key string) (keyedPanel, bool) {
+	for _, p := range panels {
+		if p.key == key {
+			return p, true
+		}
+	}
+	return keyedPanel{}, false
+}
+
+func targetsByRefID(raw any) map[string]map[string]any {
+	targets := map[string]map[string]any{}
+	items, _ := raw.([]any)
+	for i, item := range items {
+		target, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		refID, _ := target["refId"].(string)
+		if refID == "" {
+			refID = fmt.Sprintf("#%d", i+1)
+		}
+		targets[refID] = target
+	}
+	return targets
+}
+
+func expr(target map[string]any) string {
+	e, _ := target["expr"].(string)
+	return e
+}
+
+func without(m map[string]any, key string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if k != key {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,152 @@
+package grafana_test
+
+import (
+	"slices"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+)
+
+func TestDiff(t *testing.T) {
+	const base = `{
+		"uid": "api", "title": "API", "tags": ["slo"],
+		"panels": [
+			{"type": "stat", "title": "SLI", "targets": [{"refId": "A", "expr": "sli"}]},
+			{"type": "row", "title": "Details", "collapsed": true, "panels": [
+				{"type": "timeseries", "title": "Burn rate", "targets": [{"refId": "A", "expr": "burn"}]}
+			]}
+		]
+	}`
+
+	tests := []struct {
+		name     string
+		deployed string
+		desired  string
+		want     []string
+	}{
+		{
+			name:     "identical",
+			deployed: base,
+			desired:  base,
+		},
+		{
+			name:     "volatile dashboard fields",
+			deployed: `{"uid": "api", "id": 12, "version": 7, "iteration": 1700000000000}`,
+			desired:  `{"uid": "api"}`,
+		},
+		{
+			name:     "volatile panel fields",
+			deployed: `{"panels": [{"title": "SLI", "id": 3, "pluginVersion": "11.0.0"}]}`,
+			desired:  `{"panels": [{"title": "SLI", "id": 1}]}`,
+		},
+		{
+			name:     "dashboard field",
+			deployed: `{"uid": "api", "title": "API", "refresh": "1m"}`,
+			desired:  `{"uid": "api", "title": "API SLO", "description": "API"}`,
+			want:     []string{"~ dashboard: description, refresh, title"},
+		},
+		{
+			name:     "longer array",
+			deployed: `{"tags": ["slo"]}`,
+			desired:  `{"tags": ["slo", "api"]}`,
+			want:     []string{"~ dashboard: tags"},
+		},
+		{
+			name:     "shorter array",
+			deployed: `{"panels": [{"title": "SLI", "links": [{"url": "a"}, {"url": "b"}]}]}`,
+			desired:  `{"panels": [{"title": "SLI", "links": [{"url": "a"}]}]}`,
+			want:     []string{`~ panel "SLI": links`},
+		},
+		{
+			name:     "panel added and removed",
+			deployed: `{"panels": [{"title": "SLI"}, {"title": "Old"}]}`,
+			desired:  `{"panels": [{"title": "SLI"}, {"title": "New"}, {"type": "text"}]}`,
+			want:     []string{`+ panel "New"`, `+ panel "text"`, `- panel "Old"`},
+		},
+		{
+			name:     "query added",
+			deployed: `{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "a"}]}]}`,
+			desired:  `{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "a"}, {"refId": "B", "expr": "b"}]}]}`,
+			want:     []string{`+ panel "SLI" query B`},
+		},
+		{
+			name:     "query removed",
+			deployed: `{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "a"}, {"refId": "B", "expr": "b"}]}]}`,
+			desired:  `{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "a"}]}]}`,
+			want:     []string{`- panel "SLI" query B`},
+		},
+		{
+			name:     "panel nested in a collapsed row",
+			deployed: base,
+			desired: `{
+				"uid": "api", "title": "API", "tags": ["slo"],
+				"panels": [
+					{"type": "stat", "title": "SLI", "targets": [{"refId": "A", "expr": "sli"}]},
+					{"type": "row", "title": "Details", "collapsed": true, "panels": [
+						{"type": "timeseries", "title": "Burn rate", "targets": [{"refId": "A", "expr": "burn * 2"}]}
+					]}
+				]
+			}`,
+			want: []string{`~ panel "Burn rate" query A: expr`},
+		},
+		{
+			name:     "panel moved out of a collapsed row",
+			deployed: base,
+			desired: `{
+				"uid": "api", "title": "API", "tags": ["slo"],
+				"panels": [
+					{"type": "stat", "title": "SLI", "targets": [{"refId": "A", "expr": "sli"}]},
+					{"type": "row", "title": "Details", "collapsed": false, "panels": []},
+					{"type": "timeseries", "title": "Burn rate", "targets": [{"refId": "A", "expr": "burn"}]}
+				]
+			}`,
+			want: []string{`~ row "Details": collapsed`},
+		},
+		{
+			name:     "repeated titles",
+			deployed: `{"panels": [{"title": "Rate", "unit": "s"}, {"title": "Rate", "unit": "s"}]}`,
+			desired:  `{"panels": [{"title": "Rate", "unit": "s"}, {"title": "Rate", "unit": "ms"}]}`,
+			want:     []string{`~ panel "Rate" #2: unit`},
+		},
+	}
+
+	for _, tt := range tests {
+		changes, err := grafana.Diff([]byte(tt.deployed), []byte(tt.desired))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []string
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: changes %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDiffQueryExpressions(t *testing.T) {
+	changes, err := grafana.Diff(
+		[]byte(`{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "before"}]}]}`),
+		[]byte(`{"panels": [{"title": "SLI", "targets": [{"refId": "A", "expr": "after", "legendFormat": "SLI"}]}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("changes %v, want one", changes)
+	}
+	c := changes[0]
+	if c.String() != `~ panel "SLI" query A: expr, legendFormat` || c.Before != "before" || c.After != "after" {
+		t.Errorf("got %s with %q -> %q, want the expressions before and after", c, c.Before, c.After)
+	}
+}
+
+func TestDiffInvalidJSON(t *testing.T) {
+	if _, err := grafana.Diff([]byte(`{`), []byte(`{}`)); err == nil {
+		t.Errorf("got no error for an invalid deployed dashboard")
+	}
+	if _, err := grafana.Diff([]byte(`{}`), []byte(`[`)); err == nil {
+		t.Errorf("got no error for an invalid generated dashboard")
+	}
+}
@@ -0,0 +1,51 @@
+package grafana
+
+import (
+	"context"
+	"net/http"
+)
+
+// Folder is a dashboard folder
+type Folder struct {
+	UID   string `json:"uid"`
+	Title string `json:"title"`
+}
+
+// Folders lists the top-level folders
+func (c *Client) Folders(ctx context.Context) ([]Folder, error) {
+	var folders []Folder
+	if err := c.do(ctx, http.MethodGet, "/api/folders?limit=1000", nil, &folders); err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// CreateFolder creates a top-level folder, letting Grafana pick its UID
+func (c *Client) CreateFolder(ctx context.Context, title string) (Folder, error) {
+	var folder Folder
+	err := c.do(ctx, http.MethodPost, "/api/folders", map[string]string{"title": title}, &folder)
+	return folder, err
+}
+
+// FindFolder returns the folder with the given title; found is false if there is none
+func (c *Client) FindFolder(ctx context.Context, title string) (folder Folder, found bool, err error) {
+	folders, err := c.Folders(ctx)
+	if err != nil {
+		return Folder{}, false, err
+	}
+	for _, f := range folders {
+		if f.Title == title {
+			return f, true, nil
+		}
+	}
+	return Folder{}, false, nil
+}
+
+// EnsureFolder returns the folder with the given title, creating it if there is none
+func (c *Client) EnsureFolder(ctx context.Context, title string) (Folder, error) {
+	folder, found, err := c.FindFolder(ctx, title)
+	if err != nil || found {
+		return folder, err
+	}
+	return c.CreateFolder(ctx, title)
+}
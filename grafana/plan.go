@@ -0,0 +1,79 @@
+package grafana
+
+import (
+	"context"
+	"fmt"
+)
+
+// Action is what applying a plan does to a dashboard
+type Action string
+
+const (
+	ActionCreate Action = "create"
+	ActionUpdate Action = "update"
+	ActionNone   Action = "none"
+)
+
+// Plan compares one generated dashboard with the deployed one
+type Plan struct {
+	Dashboard Dashboard
+	Action    Action
+	Changes   []Change
+	Err       error
+}
+
+// generalFolder is the title Grafana shows for dashboards outside any folder
+const generalFolder = "General"
+
+// Plan fetches the deployed version of every dashboard and computes what applying would change,
+// including moving it into the folder with the given title, or the General folder if empty
+func (c *Client) Plan(ctx context.Context, folder string, dashboards []Dashboard) []Plan {
+	plans := make([]Plan, 0, len(dashboards))
+
+	target, exists := Folder{Title: generalFolder}, true
+	if folder != "" {
+		var err error
+		target, exists, err = c.FindFolder(ctx, folder)
+		if err != nil {
+			err = fmt.Errorf("folder %q: %w", folder, err)
+			for _, d := range dashboards {
+				plans = append(plans, Plan{Dashboard: d, Err: err})
+			}
+			return plans
+		}
+		target.Title = folder
+	}
+
+	for _, d := range dashboards {
+		plans = append(plans, c.plan(ctx, d, target, exists))
+	}
+	return plans
+}
+
+// plan compares d with its deployed version, which has to move unless it is in folder already.
+// A folder that doesn't exist yet holds no dashboard: apply creates it.
+func (c *Client) plan(ctx context.Context, d Dashboard, folder Folder, folderExists bool) Plan {
+	deployed, found, err := c.GetDashboard(ctx, d.UID)
+	if err != nil {
+		return Plan{Dashboard: d, Err: fmt.Errorf("fetching deployed dashboard: %w", err)}
+	}
+	if !found {
+		return Plan{Dashboard: d, Action: ActionCreate}
+	}
+
+	changes, err := Diff(deployed.Model, []byte(d.JSON))
+	if err != nil {
+		return Plan{Dashboard: d, Err: err}
+	}
+	if !folderExists || deployed.FolderUID != folder.UID {
+		before := deployed.FolderTitle
+		if deployed.FolderUID == "" {
+			before = generalFolder
+		}
+		changes = append(changes, Change{Type: Changed, Target: "folder", Before: before, After: folder.Title})
+	}
+	if len(changes) == 0 {
+		return Plan{Dashboard: d, Action: ActionNone}
+	}
+	return Plan{Dashboard: d, Action: ActionUpdate, Changes: changes}
+}
@@ -0,0 +1,87 @@
+package grafana_test
+
+import (
+	"context"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/internal/grafanatest"
+)
+
+func TestPlan(t *testing.T) {
+	fake := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"}, grafana.Folder{UID: "other", Title: "Other"})
+	client := fake.Client()
+	ctx := context.Background()
+	grafana.NewPublisher(client, "SLOs").Publish(ctx, []grafana.Dashboard{dashboard("api"), dashboard("web")})
+
+	changed := dashboard("web")
+	changed.JSON = `{"uid": "web", "title": "Web SLO"}`
+
+	plans := client.Plan(ctx, "SLOs", []grafana.Dashboard{dashboard("api"), changed, dashboard("new")})
+	for i, want := range []grafana.Action{grafana.ActionNone, grafana.ActionUpdate, grafana.ActionCreate} {
+		if plans[i].Err != nil || plans[i].Action != want {
+			t.Errorf("%s: got %s (error %v), want %s", plans[i].Dashboard.UID, plans[i].Action, plans[i].Err, want)
+		}
+	}
+	if changes := plans[1].Changes; len(changes) != 1 || changes[0].String() != "~ dashboard: title" {
+		t.Errorf("web: changes %v, want the title", changes)
+	}
+	if len(fake.Dashboards) != 2 {
+		t.Errorf("planning saved dashboards: got %d, want 2", len(fake.Dashboards))
+	}
+}
+
+func TestPlanMovesFolder(t *testing.T) {
+	tests := []struct {
+		name      string
+		folder    string
+		want      grafana.Action
+		wantAfter string
+	}{
+		{"same folder", "SLOs", grafana.ActionNone, ""},
+		{"other folder", "Other", grafana.ActionUpdate, "Other"},
+		{"General folder", "", grafana.ActionUpdate, "General"},
+		{"folder to create", "New", grafana.ActionUpdate, "New"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"}, grafana.Folder{UID: "other", Title: "Other"}).Client()
+			ctx := context.Background()
+			grafana.NewPublisher(client, "SLOs").Publish(ctx, []grafana.Dashboard{dashboard("api")})
+
+			plan := client.Plan(ctx, tt.folder, []grafana.Dashboard{dashboard("api")})[0]
+			if plan.Err != nil || plan.Action != tt.want {
+				t.Fatalf("got %s (error %v), want %s", plan.Action, plan.Err, tt.want)
+			}
+			if tt.want == grafana.ActionNone {
+				return
+			}
+			want := grafana.Change{Type: grafana.Changed, Target: "folder", Before: "SLOs", After: tt.wantAfter}
+			if len(plan.Changes) != 1 || plan.Changes[0].String() != want.String() ||
+				plan.Changes[0].Before != want.Before || plan.Changes[0].After != want.After {
+				t.Errorf("changes %+v, want %+v", plan.Changes, want)
+			}
+		})
+	}
+}
+
+func TestPlanFromGeneralFolder(t *testing.T) {
+	client := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"}).Client()
+	ctx := context.Background()
+	grafana.NewPublisher(client, "").Publish(ctx, []grafana.Dashboard{dashboard("api")})
+
+	if plan := client.Plan(ctx, "", []grafana.Dashboard{dashboard("api")})[0]; plan.Action != grafana.ActionNone {
+		t.Errorf("General to General: got %s %v, want none", plan.Action, plan.Changes)
+	}
+
+	plan := client.Plan(ctx, "SLOs", []grafana.Dashboard{dashboard("api")})[0]
+	if plan.Action != grafana.ActionUpdate || len(plan.Changes) != 1 || plan.Changes[0].Before != "General" || plan.Changes[0].After != "SLOs" {
+		t.Errorf("General to SLOs: got %s %+v, want a folder change", plan.Action, plan.Changes)
+	}
+
+	grafana.NewPublisher(client, "SLOs").Publish(ctx, []grafana.Dashboard{dashboard("api")})
+	if plan := client.Plan(ctx, "SLOs", []grafana.Dashboard{dashboard("api")})[0]; plan.Action != grafana.ActionNone {
+		t.Errorf("after apply: got %s %v, want none", plan.Action, plan.Changes)
+	}
+}
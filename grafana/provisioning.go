@@ -0,0 +1,46 @@
+package grafana
+
+import "unobravo.com/go-obs-as-code/internal/yamlfile"
+
+// ProvisioningConfig is a dashboard provisioning file, as read from provisioning/dashboards
+type ProvisioningConfig struct {
+	APIVersion int        `yaml:"apiVersion"`
+	Providers  []Provider `yaml:"providers"`
+}
+
+// Provider loads the dashboards of one directory into one folder
+type Provider struct {
+	Name                  string          `yaml:"name"`
+	OrgID                 int             `yaml:"orgId"`
+	Type                  string          `yaml:"type"`
+	Folder                string          `yaml:"folder"`
+	DisableDeletion       bool            `yaml:"disableDeletion"`
+	AllowUIUpdates        bool            `yaml:"allowUiUpdates"`
+	UpdateIntervalSeconds int             `yaml:"updateIntervalSeconds"`
+	Options               ProviderOptions `yaml:"options"`
+}
+
+type ProviderOptions struct {
+	Path string `yaml:"path"`
+}
+
+func NewProvisioningConfig(providers ...Provider) *ProvisioningConfig {
+	return &ProvisioningConfig{APIVersion: 1, Providers: providers}
+}
+
+// NewFileProvider serves the dashboards found under path from the given folder.
+// Dashboards deleted from disk are deleted from Grafana, and UI edits can't be saved.
+func NewFileProvider(name, folder, path string) Provider {
+	return Provider{
+		Name:                  name,
+		OrgID:                 1,
+		Type:                  "file",
+		Folder:                folder,
+		UpdateIntervalSeconds: 30,
+		Options:               ProviderOptions{Path: path},
+	}
+}
+
+func (c *ProvisioningConfig) ToYAML() (string, error) {
+	return yamlfile.Encode(c)
+}
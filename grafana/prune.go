@@ -0,0 +1,34 @@
+package grafana
+
+import (
+	"context"
+	"slices"
+)
+
+// StaleDashboards lists the dashboards carrying the managed tag in the folder with the given title
+// whose UID isn't in keep. An empty folder title searches every folder.
+func (c *Client) StaleDashboards(ctx context.Context, folder, tag string, keep map[string]bool) ([]DashboardHit, error) {
+	var folderUID string
+	if folder != "" {
+		f, found, err := c.FindFolder(ctx, folder)
+		if err != nil || !found {
+			return nil, err
+		}
+		folderUID = f.UID
+	}
+
+	hits, err := c.SearchDashboards(ctx, tag, folderUID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The search filters are checked again so a lenient API can't widen what gets deleted
+	var stale []DashboardHit
+	for _, hit := range hits {
+		if keep[hit.UID] || !slices.Contains(hit.Tags, tag) || (folderUID != "" && hit.FolderUID != folderUID) {
+			continue
+		}
+		stale = append(stale, hit)
+	}
+	return stale, nil
+}
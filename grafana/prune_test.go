@@ -0,0 +1,66 @@
+package grafana_test
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/internal/grafanatest"
+)
+
+const managed = "managed-by:go-obs-as-code"
+
+func staleUIDs(hits []grafana.DashboardHit) []string {
+	var uids []string
+	for _, hit := range hits {
+		uids = append(uids, hit.UID)
+	}
+	return uids
+}
+
+func TestStaleDashboards(t *testing.T) {
+	tests := []struct {
+		name    string
+		folder  string
+		lenient bool
+		want    []string
+	}{
+		{"in the folder", "SLOs", false, []string{"removed"}},
+		{"in every folder", "", false, []string{"elsewhere", "removed"}},
+		{"from a search ignoring its filters", "SLOs", true, []string{"removed"}},
+		{"in a missing folder", "Missing", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"}, grafana.Folder{UID: "other", Title: "Other"})
+			fake.AddDashboard("kept", "Kept SLO", "slos", managed)
+			fake.AddDashboard("removed", "Removed SLO", "slos", managed, "team:api")
+			fake.AddDashboard("handmade", "Handmade", "slos", "slo")
+			fake.AddDashboard("untagged", "Untagged", "slos")
+			fake.AddDashboard("elsewhere", "Elsewhere SLO", "other", managed)
+			fake.IgnoreSearchFilters = tt.lenient
+
+			stale, err := fake.Client().StaleDashboards(context.Background(), tt.folder, managed, map[string]bool{"kept": true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := staleUIDs(stale); !slices.Equal(got, tt.want) {
+				t.Errorf("stale %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStaleDashboardsSearchError(t *testing.T) {
+	fake := grafanatest.NewServer(t, "", grafana.Folder{UID: "slos", Title: "SLOs"})
+	fake.AddDashboard("removed", "Removed SLO", "slos", managed)
+	fake.SearchStatus = http.StatusInternalServerError
+
+	stale, err := fake.Client().StaleDashboards(context.Background(), "SLOs", managed, nil)
+	if err == nil || stale != nil {
+		t.Errorf("got %q and error %v, want the search error and no dashboard", staleUIDs(stale), err)
+	}
+}
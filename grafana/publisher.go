@@ -0,0 +1,78 @@
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Dashboard is a generated dashboard ready to be uploaded
+type Dashboard struct {
+	UID  string
+	JSON string
+}
+
+// Result reports how publishing a single dashboard went
+type Result struct {
+	UID     string
+	URL     string
+	Version int
+	Err     error
+}
+
+// Publisher uploads dashboards into a folder, created on first use
+type Publisher struct {
+	client  *Client
+	folder  string
+	message string
+}
+
+// NewPublisher publishes into the folder with the given title, or the General folder if empty
+func NewPublisher(client *Client, folder string) *Publisher {
+	return &Publisher{
+		client:  client,
+		folder:  folder,
+		message: "Published by go-obs-as-code",
+	}
+}
+
+// WithMessage sets the version history message of the published dashboards
+func (p *Publisher) WithMessage(message string) *Publisher {
+	p.message = message
+	return p
+}
+
+// Publish uploads every dashboard and reports each one separately; a failed upload doesn't stop the others
+func (p *Publisher) Publish(ctx context.Context, dashboards []Dashboard) []Result {
+	results := make([]Result, 0, len(dashboards))
+
+	var folderUID string
+	if p.folder != "" {
+		folder, err := p.client.EnsureFolder(ctx, p.folder)
+		if err != nil {
+			err = fmt.Errorf("folder %q: %w", p.folder, err)
+			for _, d := range dashboards {
+				results = append(results, Result{UID: d.UID, Err: err})
+			}
+			return results
+		}
+		folderUID = folder.UID
+	}
+
+	for _, d := range dashboards {
+		results = append(results, p.publish(ctx, d, folderUID))
+	}
+	return results
+}
+
+func (p *Publisher) publish(ctx context.Context, d Dashboard, folderUID string) Result {
+	if !json.Valid([]byte(d.JSON)) {
+		return Result{UID: d.UID, Err: fmt.Errorf("dashboard %s is not valid JSON", d.UID)}
+	}
+
+	saved, err := p.client.SaveDashboard(ctx, json.RawMessage(d.JSON), folderUID, p.message)
+	if err != nil {
+		return Result{UID: d.UID, Err: err}
+	}
+	return Result{UID: d.UID, URL: p.client.Link(saved.URL), Version: saved.Version}
+}
@@ -0,0 +1,174 @@
+package grafana_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/internal/grafanatest"
+)
+
+func dashboard(uid string) grafana.Dashboard {
+	return grafana.Dashboard{UID: uid, JSON: `{"uid": "` + uid + `", "title": "` + uid + `"}`}
+}
+
+func TestPublishCreatesMissingFolder(t *testing.T) {
+	fake := grafanatest.NewServer(t, "")
+	client := fake.Client()
+
+	results := grafana.NewPublisher(client, "SLOs").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+	if err := results[0].Err; err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.Folders) != 1 || fake.Folders[0].Title != "SLOs" {
+		t.Fatalf("folders = %+v, want the SLOs folder created", fake.Folders)
+	}
+	if got := fake.Dashboards["api"].FolderUID; got != "folder-slos" {
+		t.Errorf("dashboard saved in folder %q, want folder-slos", got)
+	}
+}
+
+func TestPublishReusesExistingFolder(t *testing.T) {
+	fake := grafanatest.NewServer(t, "",
+		grafana.Folder{UID: "other", Title: "Other"},
+		grafana.Folder{UID: "existing", Title: "SLOs"})
+	client := fake.Client()
+
+	results := grafana.NewPublisher(client, "SLOs").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+	if err := results[0].Err; err != nil {
+		t.Fatal(err)
+	}
+
+	for _, request := range fake.Requests {
+		if request == "POST /api/folders" {
+			t.Errorf("created a folder although SLOs exists")
+		}
+	}
+	if got := fake.Dashboards["api"].FolderUID; got != "existing" {
+		t.Errorf("dashboard saved in folder %q, want existing", got)
+	}
+}
+
+func TestPublishWithoutFolder(t *testing.T) {
+	fake := grafanatest.NewServer(t, "")
+	client := fake.Client()
+
+	results := grafana.NewPublisher(client, "").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+	if err := results[0].Err; err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"POST /api/dashboards/db"}; strings.Join(fake.Requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", fake.Requests, want)
+	}
+	if got := fake.Dashboards["api"].FolderUID; got != "" {
+		t.Errorf("dashboard saved in folder %q, want the General folder", got)
+	}
+}
+
+func TestPublishOverwritesByUID(t *testing.T) {
+	fake := grafanatest.NewServer(t, "")
+	client := fake.Client()
+	publisher := grafana.NewPublisher(client, "").WithMessage("second")
+
+	for i := 0; i < 2; i++ {
+		results := publisher.Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+		if err := results[0].Err; err != nil {
+			t.Fatalf("publish #%d: %v", i+1, err)
+		}
+		if results[0].Version != i+1 {
+			t.Errorf("publish #%d: version %d, want %d", i+1, results[0].Version, i+1)
+		}
+	}
+
+	if len(fake.Dashboards) != 1 {
+		t.Errorf("got %d dashboards, want the same one overwritten", len(fake.Dashboards))
+	}
+	if got := fake.Dashboards["api"].Message; got != "second" {
+		t.Errorf("version message %q, want second", got)
+	}
+}
+
+func TestPublishReportsEachDashboard(t *testing.T) {
+	fake := grafanatest.NewServer(t, "")
+	client := fake.Client()
+	fake.Reject["broken"] = http.StatusBadRequest
+
+	results := grafana.NewPublisher(client, "").Publish(context.Background(), []grafana.Dashboard{
+		dashboard("api"),
+		dashboard("broken"),
+		{UID: "invalid", JSON: `{"uid": `},
+		dashboard("web"),
+	})
+	if len(results) != 4 {
+		t.Fatalf("got %d results, want one per dashboard", len(results))
+	}
+
+	for _, i := range []int{0, 3} {
+		if results[i].Err != nil {
+			t.Errorf("%s: %v", results[i].UID, results[i].Err)
+		}
+	}
+
+	var apiErr *grafana.APIError
+	if !errors.As(results[1].Err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest || apiErr.Message != "dashboard broken rejected" {
+		t.Errorf("broken: got error %v, want Grafana's 400 response", results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Errorf("invalid: got no error for a dashboard that isn't JSON")
+	}
+	if _, sent := fake.Dashboards["invalid"]; sent {
+		t.Errorf("invalid: sent to Grafana")
+	}
+}
+
+func TestPublishFolderErrorFailsEveryDashboard(t *testing.T) {
+	client := grafanatest.NewServer(t, "").Client()
+	client.Token = "wrong"
+
+	results := grafana.NewPublisher(client, "SLOs").Publish(context.Background(), []grafana.Dashboard{dashboard("api"), dashboard("web")})
+	for _, result := range results {
+		var apiErr *grafana.APIError
+		if !errors.As(result.Err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+			t.Errorf("%s: got error %v, want the folder lookup's 401", result.UID, result.Err)
+		}
+	}
+}
+
+func TestPublishSendsToken(t *testing.T) {
+	fake := grafanatest.NewServer(t, "")
+	client := fake.Client()
+	fake.Folders = []grafana.Folder{{UID: "slos", Title: "SLOs"}}
+
+	results := grafana.NewPublisher(client, "SLOs").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+	if err := results[0].Err; err != nil {
+		t.Fatalf("got %v, want the token accepted by every request", err)
+	}
+
+	client.Token = ""
+	results = grafana.NewPublisher(client, "").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+	var apiErr *grafana.APIError
+	if !errors.As(results[0].Err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("got error %v without a token, want 401", results[0].Err)
+	}
+}
+
+func TestPublishURLUnderSubPath(t *testing.T) {
+	for _, subPath := range []string{"", "/grafana"} {
+		client := grafanatest.NewServer(t, subPath).Client()
+
+		results := grafana.NewPublisher(client, "").Publish(context.Background(), []grafana.Dashboard{dashboard("api")})
+		if err := results[0].Err; err != nil {
+			t.Fatalf("%q: %v", subPath, err)
+		}
+
+		want := strings.TrimSuffix(client.URL, subPath) + subPath + "/d/api/api"
+		if results[0].URL != want {
+			t.Errorf("%q: URL %s, want %s", subPath, results[0].URL, want)
+		}
+	}
+}
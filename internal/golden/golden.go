@@ -0,0 +1,41 @@
+// Package golden compares generated files against the copies committed under testdata.
+package golden
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/internal/textdiff"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files with the generated output")
+
+// Assert fails the test with a unified diff when got differs from testdata/<name>,
+// or rewrites the golden file when the tests run with -update
+func Assert(t testing.TB, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading golden file: %v (run the tests with -update to create it)", err)
+	}
+
+	var diff strings.Builder
+	if textdiff.Unified(&diff, path, name+" (generated)", string(want), got) {
+		t.Errorf("generated output differs from %s (run the tests with -update if the change is intended):\n%s", path, diff.String())
+	}
+}
@@ -0,0 +1,236 @@
+// Package grafanatest fakes the parts of the Grafana HTTP API the grafana package uses,
+// keeping folders and dashboards in memory.
+package grafanatest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"unobravo.com/go-obs-as-code/grafana"
+)
+
+// Token is the only service account token the server accepts
+const Token = "glsa_test"
+
+// Server is a fake Grafana serving the folder, dashboard and search endpoints under SubPath.
+// Its fields may be changed between requests.
+type Server struct {
+	// URL is the base URL of the API, SubPath included
+	URL     string
+	SubPath string
+
+	mu         sync.Mutex
+	Folders    []grafana.Folder
+	Dashboards map[string]Dashboard
+
+	// Requests lists every request received, e.g. "POST /api/folders"
+	Requests []string
+
+	// Reject fails the save of the dashboards with these UIDs with the given status
+	Reject map[string]int
+
+	// SearchStatus fails every search with this status, unless it is 0
+	SearchStatus int
+
+	// IgnoreSearchFilters returns every dashboard from a search, as a lenient API might
+	IgnoreSearchFilters bool
+}
+
+// Dashboard is a dashboard saved in the server
+type Dashboard struct {
+	Model     json.RawMessage
+	FolderUID string
+	Message   string
+	Version   int
+}
+
+// NewServer starts a fake Grafana holding the given folders, closed at the end of the test
+func NewServer(t testing.TB, subPath string, folders ...grafana.Folder) *Server {
+	s := &Server{
+		SubPath:    subPath,
+		Folders:    folders,
+		Dashboards: map[string]Dashboard{},
+		Reject:     map[string]int{},
+	}
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	s.URL = server.URL + subPath
+	return s
+}
+
+// Client returns a client of the server authenticated with Token
+func (s *Server) Client() *grafana.Client {
+	return grafana.NewClient(s.URL+"/", Token)
+}
+
+// AddDashboard stores a dashboard as if it had been saved already
+func (s *Server) AddDashboard(uid, title, folderUID string, tags ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	model, _ := json.Marshal(map[string]any{"uid": uid, "title": title, "tags": tags})
+	s.Dashboards[uid] = Dashboard{Model: model, FolderUID: folderUID, Version: 1}
+}
+
+// Deleted lists the UIDs of every dashboard a DELETE request was received for
+func (s *Server) Deleted() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var uids []string
+	for _, request := range s.Requests {
+		if uid, ok := strings.CutPrefix(request, "DELETE /api/dashboards/uid/"); ok {
+			uids = append(uids, uid)
+		}
+	}
+	return uids
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, ok := strings.CutPrefix(r.URL.Path, s.SubPath)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	s.Requests = append(s.Requests, r.Method+" "+path)
+
+	if r.Header.Get("Authorization") != "Bearer "+Token {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"message": "invalid API key"})
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && path == "/api/folders":
+		writeJSON(w, http.StatusOK, s.Folders)
+
+	case r.Method == http.MethodPost && path == "/api/folders":
+		var body struct {
+			Title string `json:"title"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+			return
+		}
+		folder := grafana.Folder{UID: "folder-" + strings.ToLower(body.Title), Title: body.Title}
+		s.Folders = append(s.Folders, folder)
+		writeJSON(w, http.StatusOK, folder)
+
+	case r.Method == http.MethodPost && path == "/api/dashboards/db":
+		s.save(w, r)
+
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "/api/dashboards/uid/"):
+		saved, ok := s.Dashboards[strings.TrimPrefix(path, "/api/dashboards/uid/")]
+		if !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Dashboard not found"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]any{
+			"dashboard": saved.Model,
+			"meta":      map[string]string{"folderUid": saved.FolderUID, "folderTitle": s.folderTitle(saved.FolderUID)},
+		})
+
+	case r.Method == http.MethodDelete && strings.HasPrefix(path, "/api/dashboards/uid/"):
+		uid := strings.TrimPrefix(path, "/api/dashboards/uid/")
+		if _, ok := s.Dashboards[uid]; !ok {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Dashboard not found"})
+			return
+		}
+		delete(s.Dashboards, uid)
+		writeJSON(w, http.StatusOK, map[string]string{"message": "Dashboard deleted"})
+
+	case r.Method == http.MethodGet && path == "/api/search":
+		if s.SearchStatus != 0 {
+			writeJSON(w, s.SearchStatus, map[string]string{"message": "search failed"})
+			return
+		}
+		writeJSON(w, http.StatusOK, s.search(r))
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (s *Server) save(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Dashboard json.RawMessage `json:"dashboard"`
+		FolderUID string          `json:"folderUid"`
+		Message   string          `json:"message"`
+		Overwrite bool            `json:"overwrite"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+	var model struct {
+		UID string `json:"uid"`
+	}
+	if err := json.Unmarshal(body.Dashboard, &model); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"message": err.Error()})
+		return
+	}
+
+	uid := model.UID
+	if status, ok := s.Reject[uid]; ok {
+		writeJSON(w, status, map[string]string{"message": "dashboard " + uid + " rejected"})
+		return
+	}
+	existing, exists := s.Dashboards[uid]
+	if exists && !body.Overwrite {
+		writeJSON(w, http.StatusPreconditionFailed, map[string]string{"message": "A dashboard with the same uid already exists"})
+		return
+	}
+	saved := Dashboard{Model: body.Dashboard, FolderUID: body.FolderUID, Message: body.Message, Version: existing.Version + 1}
+	s.Dashboards[uid] = saved
+	writeJSON(w, http.StatusOK, grafana.SaveResult{
+		UID:     uid,
+		URL:     s.SubPath + "/d/" + uid + "/" + uid,
+		Status:  "success",
+		Version: saved.Version,
+	})
+}
+
+// search matches dashboards on every tag and any of the folders, like Grafana's /api/search
+func (s *Server) search(r *http.Request) []grafana.DashboardHit {
+	query := r.URL.Query()
+	hits := []grafana.DashboardHit{}
+	for uid, saved := range s.Dashboards {
+		var model struct {
+			Title string   `json:"title"`
+			Tags  []string `json:"tags"`
+		}
+		_ = json.Unmarshal(saved.Model, &model)
+
+		matches := !slices.ContainsFunc(query["tag"], func(tag string) bool { return !slices.Contains(model.Tags, tag) })
+		if folders := query["folderUIDs"]; len(folders) > 0 && !slices.Contains(folders, saved.FolderUID) {
+			matches = false
+		}
+		if matches || s.IgnoreSearchFilters {
+			hits = append(hits, grafana.DashboardHit{UID: uid, Title: model.Title, Tags: model.Tags, FolderUID: saved.FolderUID})
+		}
+	}
+	slices.SortFunc(hits, func(a, b grafana.DashboardHit) int { return strings.Compare(a.UID, b.UID) })
+	return hits
+}
+
+func (s *Server) folderTitle(uid string) string {
+	for _, folder := range s.Folders {
+		if folder.UID == uid {
+			return folder.Title
+		}
+	}
+	return "General"
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,128 @@
+// Package promtest evaluates PromQL expressions offline, against synthetic series
+// written in the notation of promtool's unit tests.
+package promtest
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/promql"
+	"github.com/prometheus/prometheus/promql/promqltest"
+	"github.com/prometheus/prometheus/storage"
+)
+
+// maxSamples is high enough for subqueries over a whole 28d SLO window
+const maxSamples = 50_000_000
+
+// Grafana is the value of Grafana's built-in variables when expressions are evaluated,
+// standing in for a dashboard whose time range ends at the evaluation time
+type Grafana struct {
+	RateInterval time.Duration
+	Interval     time.Duration
+	Range        time.Duration
+}
+
+// DefaultGrafana is a 1h dashboard range charted at 1m resolution, over series sampled every minute
+var DefaultGrafana = Grafana{RateInterval: 4 * time.Minute, Interval: time.Minute, Range: time.Hour}
+
+// Storage holds synthetic series and evaluates expressions against them.
+// Like promtool, the series start at the Unix epoch and times are offsets from it.
+type Storage struct {
+	t       testing.TB
+	storage storage.Storage
+	engine  *promql.Engine
+
+	// Grafana replaces the built-in variables of dashboard queries
+	Grafana Grafana
+
+	// Variables replaces dashboard template variables, $name or ${name}
+	Variables map[string]string
+}
+
+// Load stores the series of one or more load commands, e.g.
+//
+//	load 1m
+//	  http_requests_total{code="500"} 0+2x60
+//	  http_requests_total{code="200"} 0+98x60
+func Load(t testing.TB, input string) *Storage {
+	t.Helper()
+	s := promqltest.LoadedStorage(t, input)
+	t.Cleanup(func() { s.Close() })
+	return &Storage{
+		t:       t,
+		storage: s,
+		engine:  promqltest.NewTestEngine(t, false, 0, maxSamples),
+		Grafana: DefaultGrafana,
+	}
+}
+
+// Eval evaluates expr at the given offset from the start of the series.
+// Dashboard variables are replaced first, errors fail the test.
+func (s *Storage) Eval(expr string, at time.Duration) promql.Vector {
+	s.t.Helper()
+	expanded := s.Expand(expr, at)
+	query, err := s.engine.NewInstantQuery(context.Background(), s.storage, nil, expanded, time.Unix(0, 0).Add(at))
+	if err != nil {
+		s.t.Fatalf("parsing %s: %v", expanded, err)
+	}
+	defer query.Close()
+
+	result := query.Exec(context.Background())
+	if result.Err != nil {
+		s.t.Fatalf("evaluating %s at %s: %v", expanded, at, result.Err)
+	}
+	switch v := result.Value.(type) {
+	case promql.Vector:
+		return v
+	case promql.Scalar:
+		return promql.Vector{{T: v.T, F: v.V}}
+	default:
+		s.t.Fatalf("evaluating %s at %s: got a %s, want an instant vector", expanded, at, result.Value.Type())
+		return nil
+	}
+}
+
+// Value evaluates expr at the given offset and returns its only sample,
+// failing the test when the result has no sample or several
+func (s *Storage) Value(expr string, at time.Duration) float64 {
+	s.t.Helper()
+	vector := s.Eval(expr, at)
+	if len(vector) != 1 {
+		s.t.Fatalf("evaluating %s at %s: got %d samples, want 1: %v", s.Expand(expr, at), at, len(vector), vector)
+	}
+	return vector[0].F
+}
+
+// Expand replaces Grafana's built-in variables and the template variables in expr,
+// as Grafana would for a dashboard whose time range ends at the given offset
+func (s *Storage) Expand(expr string, at time.Duration) string {
+	replacements := []string{
+		"${__to:date:seconds}", strconv.FormatInt(int64(at/time.Second), 10),
+		"$__rate_interval", promDuration(s.Grafana.RateInterval),
+		"$__interval", promDuration(s.Grafana.Interval),
+		"$__range", promDuration(s.Grafana.Range),
+	}
+	// Longer names first, so $job doesn't replace the start of $job_name
+	names := slices.Collect(maps.Keys(s.Variables))
+	slices.SortFunc(names, func(a, b string) int { return cmp.Or(len(b)-len(a), strings.Compare(a, b)) })
+	for _, name := range names {
+		replacements = append(replacements, "${"+name+"}", s.Variables[name], "$"+name, s.Variables[name])
+	}
+	return strings.NewReplacer(replacements...).Replace(expr)
+}
+
+// promDuration renders d as a PromQL duration, e.g. 90s or 5m
+func promDuration(d time.Duration) string {
+	if d%time.Minute == 0 {
+		return fmt.Sprintf("%dm", d/time.Minute)
+	}
+	return fmt.Sprintf("%ds", int64(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,151 @@
+// Package textdiff renders line-based unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const diffContext = 3
+
+type editKind int
+
+const (
+	editEqual editKind = iota
+	editDelete
+	editInsert
+)
+
+type edit struct {
+	kind editKind
+	line string
+}
+
+// Unified writes a unified diff of two texts and reports whether they differ
+func Unified(w io.Writer, fromName, toName, from, to string) bool {
+	if from == to {
+		return false
+	}
+
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	fmt.Fprintf(w, "--- %s\n+++ %s\n", fromName, toName)
+	for start := 0; start < len(edits); {
+		// Find the next change and the end of the hunk around it
+		first := start
+		for first < len(edits) && edits[first].kind == editEqual {
+			first++
+		}
+		if first == len(edits) {
+			break
+		}
+
+		end := first
+		for end < len(edits) {
+			if edits[end].kind != editEqual {
+				end++
+				continue
+			}
+			run := end
+			for run < len(edits) && edits[run].kind == editEqual {
+				run++
+			}
+			if run == len(edits) || run-end > 2*diffContext {
+				break
+			}
+			end = run
+		}
+
+		hunkStart := max(first-diffContext, start)
+		hunkEnd := min(end+diffContext, len(edits))
+		writeHunk(w, edits, hunkStart, hunkEnd)
+		start = hunkEnd
+	}
+
+	return true
+}
+
+func writeHunk(w io.Writer, edits []edit, start, end int) {
+	fromLine, toLine := 1, 1
+	for _, e := range edits[:start] {
+		if e.kind != editInsert {
+			fromLine++
+		}
+		if e.kind != editDelete {
+			toLine++
+		}
+	}
+
+	fromCount, toCount := 0, 0
+	for _, e := range edits[start:end] {
+		if e.kind != editInsert {
+			fromCount++
+		}
+		if e.kind != editDelete {
+			toCount++
+		}
+	}
+
+	fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", fromLine, fromCount, toLine, toCount)
+	for _, e := range edits[start:end] {
+		switch e.kind {
+		case editEqual:
+			fmt.Fprintf(w, " %s\n", e.line)
+		case editDelete:
+			fmt.Fprintf(w, "-%s\n", e.line)
+		case editInsert:
+			fmt.Fprintf(w, "+%s\n", e.line)
+		}
+	}
+}
+
+// diffLines computes a line edit script from the longest common subsequence
+func diffLines(from, to []string) []edit {
+	n, m := len(from), len(to)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var edits []edit
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case from[i] == to[j]:
+			edits = append(edits, edit{editEqual, from[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, edit{editDelete, from[i]})
+			i++
+		default:
+			edits = append(edits, edit{editInsert, to[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		edits = append(edits, edit{editDelete, from[i]})
+	}
+	for ; j < m; j++ {
+		edits = append(edits, edit{editInsert, to[j]})
+	}
+
+	return edits
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
@@ -0,0 +1,24 @@
+// Package yamlfile renders the generated YAML files, all in the same layout.
+package yamlfile
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Encode renders v as a YAML document indented by two spaces
+func Encode(v any) (string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(v); err != nil {
+		return "", err
+	}
+	if err := encoder.Close(); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,84 @@
+// Package kubernetes wraps generated dashboards and rules in custom resources
+// of the Grafana Operator and the Prometheus Operator.
+package kubernetes
+
+import (
+	"regexp"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/internal/yamlfile"
+	"unobravo.com/go-obs-as-code/rules"
+)
+
+// ObjectMeta is the subset of Kubernetes object metadata the manifests use
+type ObjectMeta struct {
+	Name        string            `yaml:"name"`
+	Namespace   string            `yaml:"namespace,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+// GrafanaDashboard is a dashboard of the Grafana Operator
+type GrafanaDashboard struct {
+	APIVersion string               `yaml:"apiVersion"`
+	Kind       string               `yaml:"kind"`
+	Metadata   ObjectMeta           `yaml:"metadata"`
+	Spec       GrafanaDashboardSpec `yaml:"spec"`
+}
+
+type GrafanaDashboardSpec struct {
+	InstanceSelector LabelSelector `yaml:"instanceSelector"`
+	Folder           string        `yaml:"folder,omitempty"`
+	JSON             string        `yaml:"json"`
+}
+
+// LabelSelector picks the Grafana instances a dashboard is deployed to
+type LabelSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+// PrometheusRule is a rule file of the Prometheus Operator
+type PrometheusRule struct {
+	APIVersion string             `yaml:"apiVersion"`
+	Kind       string             `yaml:"kind"`
+	Metadata   ObjectMeta         `yaml:"metadata"`
+	Spec       PrometheusRuleSpec `yaml:"spec"`
+}
+
+type PrometheusRuleSpec struct {
+	Groups []rules.Group `yaml:"groups"`
+}
+
+func NewGrafanaDashboard(meta ObjectMeta, instanceSelector map[string]string, folder, dashboardJSON string) *GrafanaDashboard {
+	return &GrafanaDashboard{
+		APIVersion: "grafana.integreatly.org/v1beta1",
+		Kind:       "GrafanaDashboard",
+		Metadata:   meta,
+		Spec: GrafanaDashboardSpec{
+			InstanceSelector: LabelSelector{MatchLabels: instanceSelector},
+			Folder:           folder,
+			JSON:             dashboardJSON,
+		},
+	}
+}
+
+func NewPrometheusRule(meta ObjectMeta, groups ...rules.Group) *PrometheusRule {
+	return &PrometheusRule{
+		APIVersion: "monitoring.coreos.com/v1",
+		Kind:       "PrometheusRule",
+		Metadata:   meta,
+		Spec:       PrometheusRuleSpec{Groups: groups},
+	}
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// Name turns a UID into a valid object name: lowercase alphanumerics, '-' and '.'
+func Name(uid string) string {
+	return strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(uid), "-"), "-.")
+}
+
+// ToYAML renders a manifest
+func ToYAML(manifest any) (string, error) {
+	return yamlfile.Encode(manifest)
+}
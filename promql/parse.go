@@ -0,0 +1,173 @@
+package promql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSelector parses a PromQL instant vector selector such as
+// http_requests_total{job="api", code=~"5.."}. The metric name may be omitted.
+func ParseSelector(input string) (Selector, error) {
+	p := &parser{input: strings.TrimSpace(input)}
+
+	selector := Selector{Metric: p.name(true)}
+	if p.skipSpaces(); p.done() {
+		if selector.Metric == "" {
+			return Selector{}, fmt.Errorf("empty selector")
+		}
+		return selector, nil
+	}
+
+	if !p.consume("{") {
+		return Selector{}, p.errorf("expected '{'")
+	}
+	for {
+		p.skipSpaces()
+		if p.consume("}") {
+			break
+		}
+
+		matcher, err := p.matcher()
+		if err != nil {
+			return Selector{}, err
+		}
+		selector.Matchers = append(selector.Matchers, matcher)
+
+		p.skipSpaces()
+		if p.consume(",") {
+			continue
+		}
+		if !p.consume("}") {
+			return Selector{}, p.errorf("expected ',' or '}'")
+		}
+		break
+	}
+
+	p.skipSpaces()
+	if !p.done() {
+		return Selector{}, p.errorf("unexpected trailing input")
+	}
+	if selector.Metric == "" && len(selector.Matchers) == 0 {
+		return Selector{}, fmt.Errorf("empty selector")
+	}
+	return selector, nil
+}
+
+// ParseMatcher parses a single label matcher such as code=~"5.."
+func ParseMatcher(input string) (Matcher, error) {
+	p := &parser{input: strings.TrimSpace(input)}
+
+	matcher, err := p.matcher()
+	if err != nil {
+		return Matcher{}, err
+	}
+	if p.skipSpaces(); !p.done() {
+		return Matcher{}, p.errorf("unexpected trailing input")
+	}
+	return matcher, nil
+}
+
+type parser struct {
+	input string
+	pos   int
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.input)
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("invalid selector %q at offset %d: %s", p.input, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) skipSpaces() {
+	for !p.done() && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t' || p.input[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+func (p *parser) consume(token string) bool {
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+// name reads a metric name (colons allowed) or a label name
+func (p *parser) name(metric bool) string {
+	start := p.pos
+	for !p.done() {
+		c := p.input[p.pos]
+		isLetter := c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (metric && c == ':')
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && !(isDigit && p.pos > start) {
+			break
+		}
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func (p *parser) matcher() (Matcher, error) {
+	p.skipSpaces()
+	label := p.name(false)
+	if label == "" {
+		return Matcher{}, p.errorf("expected label name")
+	}
+
+	p.skipSpaces()
+	var matchType MatchType
+	switch {
+	case p.consume(string(MatchRegexp)):
+		matchType = MatchRegexp
+	case p.consume(string(MatchNotRegexp)):
+		matchType = MatchNotRegexp
+	case p.consume(string(MatchNotEqual)):
+		matchType = MatchNotEqual
+	case p.consume(string(MatchEqual)):
+		matchType = MatchEqual
+	default:
+		return Matcher{}, p.errorf("expected one of =, !=, =~, !~")
+	}
+
+	p.skipSpaces()
+	value, err := p.quoted()
+	if err != nil {
+		return Matcher{}, err
+	}
+
+	return Matcher{Label: label, Type: matchType, Value: value}, nil
+}
+
+func (p *parser) quoted() (string, error) {
+	if p.done() || (p.input[p.pos] != '"' && p.input[p.pos] != '\'') {
+		return "", p.errorf("expected quoted label value")
+	}
+
+	quote := p.input[p.pos]
+	end := p.pos + 1
+	for end < len(p.input) && p.input[end] != quote {
+		if p.input[end] == '\\' {
+			end++
+		}
+		end++
+	}
+	if end >= len(p.input) {
+		return "", p.errorf("unterminated label value")
+	}
+
+	literal := p.input[p.pos : end+1]
+	if quote == '\'' {
+		inner := strings.ReplaceAll(literal[1:len(literal)-1], `\'`, `'`)
+		literal = `"` + strings.ReplaceAll(inner, `"`, `\"`) + `"`
+	}
+	value, err := strconv.Unquote(literal)
+	if err != nil {
+		return "", p.errorf("invalid label value: %v", err)
+	}
+
+	p.pos = end + 1
+	return value, nil
+}
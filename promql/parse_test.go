@@ -0,0 +1,83 @@
+package promql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSelector(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Selector
+	}{
+		{`up`, NewSelector("up")},
+		{`job:requests:rate5m`, NewSelector("job:requests:rate5m")},
+		{`up{}`, NewSelector("up")},
+		{`up {job="api"}`, NewSelector("up", Equal("job", "api"))},
+		{` up{ job = "api" } `, NewSelector("up", Equal("job", "api"))},
+		{`{job="api"}`, NewSelector("", Equal("job", "api"))},
+		{
+			`http_requests_total{job="api", code!="200", path=~"/v1/.*", method!~"GET|HEAD"}`,
+			NewSelector("http_requests_total", Equal("job", "api"), NotEqual("code", "200"),
+				Regexp("path", "/v1/.*"), NotRegexp("method", "GET|HEAD")),
+		},
+		{`up{job="api",}`, NewSelector("up", Equal("job", "api"))},
+		{`up{job="api", code="200" , }`, NewSelector("up", Equal("job", "api"), Equal("code", "200"))},
+		{`up{path=~"\\d+\\.html"}`, NewSelector("up", Regexp("path", `\d+\.html`))},
+		{`up{quote="say \"hi\""}`, NewSelector("up", Equal("quote", `say "hi"`))},
+		{`up{line="a\nb"}`, NewSelector("up", Equal("line", "a\nb"))},
+		{`up{job='it\'s', quote='"'}`, NewSelector("up", Equal("job", "it's"), Equal("quote", `"`))},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSelector(tt.input)
+		if err != nil {
+			t.Errorf("ParseSelector(%s): %v", tt.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSelector(%s) = %#v, want %#v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	inputs := []string{
+		``,
+		`   `,
+		`{}`,
+		`up{`,
+		`up}`,
+		`up{job}`,
+		`up{job="api"`,
+		`up{job="api" code="200"}`,
+		`up{job=="api"}`,
+		`up{job=api}`,
+		`up{job="api}`,
+		`up{,}`,
+		`up{job="api",,}`,
+		`up{1job="api"}`,
+		`up{job="\q"}`,
+		`up{job="api"} extra`,
+		`up extra`,
+		`1up`,
+	}
+
+	for _, input := range inputs {
+		if got, err := ParseSelector(input); err == nil {
+			t.Errorf("ParseSelector(%s) = %s, want an error", input, got)
+		}
+	}
+}
+
+func TestSelectorRoundTrip(t *testing.T) {
+	selector := NewSelector("http_requests_total", Equal("job", "api"), Regexp("path", `\d+ "quoted"`))
+
+	got, err := ParseSelector(selector.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, selector) {
+		t.Errorf("ParseSelector(%s) = %#v, want %#v", selector, got, selector)
+	}
+}
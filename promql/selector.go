@@ -0,0 +1,118 @@
+package promql
+
+import (
+	"strconv"
+	"strings"
+)
+
+// MatchType is the operator of a label matcher
+type MatchType string
+
+const (
+	MatchEqual     MatchType = "="
+	MatchNotEqual  MatchType = "!="
+	MatchRegexp    MatchType = "=~"
+	MatchNotRegexp MatchType = "!~"
+)
+
+// Matcher matches the value of a single label
+type Matcher struct {
+	Label string
+	Type  MatchType
+	Value string
+}
+
+func Equal(label, value string) Matcher {
+	return Matcher{Label: label, Type: MatchEqual, Value: value}
+}
+
+func NotEqual(label, value string) Matcher {
+	return Matcher{Label: label, Type: MatchNotEqual, Value: value}
+}
+
+func Regexp(label, value string) Matcher {
+	return Matcher{Label: label, Type: MatchRegexp, Value: value}
+}
+
+func NotRegexp(label, value string) Matcher {
+	return Matcher{Label: label, Type: MatchNotRegexp, Value: value}
+}
+
+func (m Matcher) String() string {
+	return m.Label + string(m.Type) + strconv.Quote(m.Value)
+}
+
+// Selector selects the series of a metric through label matchers
+type Selector struct {
+	Metric   string
+	Matchers []Matcher
+}
+
+func NewSelector(metric string, matchers ...Matcher) Selector {
+	return Selector{Metric: metric, Matchers: matchers}
+}
+
+// With returns a copy of the selector with extra matchers appended
+func (s Selector) With(matchers ...Matcher) Selector {
+	merged := make([]Matcher, 0, len(s.Matchers)+len(matchers))
+	merged = append(merged, s.Matchers...)
+	merged = append(merged, matchers...)
+	return Selector{Metric: s.Metric, Matchers: merged}
+}
+
+// Merge returns a copy of the selector on top of shared base matchers.
+// Base matchers on a label the selector already matches are dropped.
+func (s Selector) Merge(base []Matcher) Selector {
+	own := map[string]bool{}
+	for _, m := range s.Matchers {
+		own[m.Label] = true
+	}
+
+	merged := make([]Matcher, 0, len(base)+len(s.Matchers))
+	for _, m := range base {
+		if !own[m.Label] {
+			merged = append(merged, m)
+		}
+	}
+	merged = append(merged, s.Matchers...)
+
+	return Selector{Metric: s.Metric, Matchers: merged}
+}
+
+func (s Selector) String() string {
+	if len(s.Matchers) == 0 {
+		return s.Metric
+	}
+
+	matchers := make([]string, 0, len(s.Matchers))
+	for _, m := range s.Matchers {
+		matchers = append(matchers, m.String())
+	}
+	return s.Metric + "{" + strings.Join(matchers, ", ") + "}"
+}
+
+// Histogram is a Prometheus classic histogram identified by its base metric name
+type Histogram struct {
+	Name     string
+	Matchers []Matcher
+}
+
+func NewHistogram(name string, matchers ...Matcher) Histogram {
+	return Histogram{Name: name, Matchers: matchers}
+}
+
+// Bucket selects the cumulative bucket counting observations less than or equal to le
+func (h Histogram) Bucket(le string) Selector {
+	return NewSelector(h.Name+"_bucket", h.Matchers...).With(Equal("le", le))
+}
+
+// Count selects the total number of observations
+func (h Histogram) Count() Selector {
+	return NewSelector(h.Name+"_count", h.Matchers...)
+}
+
+// Merge returns a copy of the histogram on top of shared base matchers
+func (h Histogram) Merge(base []Matcher) Histogram {
+	merged := NewSelector(h.Name, h.Matchers...).Merge(base)
+	return Histogram{Name: h.Name, Matchers: merged.Matchers}
+}
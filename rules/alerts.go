@@ -0,0 +1,92 @@
+package rules
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// Options configures the generated alerting rules
+type Options struct {
+	// GrafanaURL is used to link alerts to their dashboard, e.g. https://unobravo.grafana.net
+	GrafanaURL string
+
+	// Labels are added to every alert, to route its notifications
+	Labels map[string]string
+}
+
+// AlertGroup builds one alert per entry of the SLO's burn-rate policy
+func AlertGroup(s slo.SLO, opts Options) Group {
+	info := s.Info()
+
+	rules := make([]Rule, 0, len(info.BurnRate))
+	for _, alert := range info.BurnRate {
+		rules = append(rules, alertRule(s, alert, opts))
+	}
+
+	return Group{
+		Name:  alertGroupName(info),
+		Rules: rules,
+	}
+}
+
+func alertGroupName(info slo.Info) string {
+	return "slo-" + info.UID + "-alerts"
+}
+
+func alertRule(s slo.SLO, alert slo.BurnRateAlert, opts Options) Rule {
+	info := s.Info()
+
+	descriptions := make([]string, 0, len(alert.Windows))
+	for _, w := range alert.Windows {
+		descriptions = append(descriptions, fmt.Sprintf("%sx over %s and %s", slo.FormatFactor(w.Factor(info.TimeWindow)), w.Long, w.Short))
+	}
+
+	labels := map[string]string{}
+	for k, v := range opts.Labels {
+		labels[k] = v
+	}
+	for k, v := range info.Labels {
+		labels[k] = v
+	}
+	labels["slo"] = info.UID
+	labels["severity"] = alert.Severity
+
+	annotations := map[string]string{
+		"summary": fmt.Sprintf("%s is burning its error budget too fast", info.Name),
+		"description": fmt.Sprintf("The error budget of %s (%s over %s) is burning faster than %s.",
+			info.Name, slo.FormatPercent(info.Target), info.TimeWindow, strings.Join(descriptions, ", or ")),
+		"dashboard_uid": info.UID,
+	}
+	if opts.GrafanaURL != "" {
+		annotations["dashboard_url"] = strings.TrimSuffix(opts.GrafanaURL, "/") + "/d/" + info.UID
+	}
+
+	rule := Rule{
+		Alert:       alert.Name,
+		Expr:        s.Queries().BurnRateConditionQuery(alert),
+		Labels:      labels,
+		Annotations: annotations,
+	}
+	if alert.For != 0 {
+		rule.For = FormatDuration(alert.For)
+	}
+	return rule
+}
+
+// FormatDuration renders a duration the way Prometheus does, e.g. 2m rather than 2m0s, and 0 as 0m
+func FormatDuration(d time.Duration) string {
+	if d == 0 {
+		return "0m"
+	}
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
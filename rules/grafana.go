@@ -0,0 +1,62 @@
+package rules
+
+import (
+	"errors"
+	"strconv"
+
+	"unobravo.com/go-obs-as-code/grafana"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// queryRange is how far back the query node of a Grafana rule looks, in seconds
+const queryRange = 600
+
+// GrafanaAlertGroup builds the Grafana-managed counterpart of AlertGroup, evaluated every interval
+// and stored in the given folder
+func GrafanaAlertGroup(s slo.SLO, folder, interval string, opts Options) (grafana.AlertRuleGroup, error) {
+	grafanaRules, err := GrafanaAlertRules(s, opts)
+	if err != nil {
+		return grafana.AlertRuleGroup{}, err
+	}
+	return grafana.NewAlertRuleGroup(alertGroupName(s.Info()), folder, interval, grafanaRules...), nil
+}
+
+// GrafanaAlertRules builds one Grafana-managed alert rule per entry of the SLO's burn-rate policy,
+// with the labels and annotations of the Prometheus alerts.
+// Each rule links to the stat panel showing its alert on the SLO's dashboard.
+// Rules query the SLO's datasource UID: a datasource variable with no UID to preselect is an error.
+func GrafanaAlertRules(s slo.SLO, opts Options) ([]grafana.AlertRule, error) {
+	info := s.Info()
+
+	datasource := info.Datasource.UID
+	if datasource == "" {
+		return nil, errors.New("Grafana-managed alerts can't query a datasource variable, they need a datasource uid")
+	}
+
+	grafanaRules := make([]grafana.AlertRule, 0, len(info.BurnRate))
+	for i, alert := range info.BurnRate {
+		prometheus := alertRule(s, alert, opts)
+
+		annotations := prometheus.Annotations
+		annotations[grafana.DashboardUIDAnnotation] = info.UID
+		annotations[grafana.PanelIDAnnotation] = strconv.FormatUint(uint64(slo.BurnRateAlertPanelID(i)), 10)
+
+		title := info.UID + " " + alert.Name
+		grafanaRules = append(grafanaRules, grafana.AlertRule{
+			UID:       grafana.AlertRuleUID(title),
+			Title:     title,
+			Condition: "C",
+			Data: []grafana.AlertQuery{
+				grafana.NewPrometheusQuery("A", datasource, s.Queries().BurnRateAlertQuery(alert), queryRange),
+				grafana.NewReduceExpression("B", "A", "last"),
+				grafana.NewThresholdExpression("C", "B", 0),
+			},
+			NoDataState:  "NoData",
+			ExecErrState: "Error",
+			For:          prometheus.For,
+			Annotations:  annotations,
+			Labels:       prometheus.Labels,
+		})
+	}
+	return grafanaRules, nil
+}
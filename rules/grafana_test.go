@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"testing"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+func TestGrafanaAlertRulesDatasource(t *testing.T) {
+	tests := []struct {
+		name       string
+		datasource slo.Datasource
+		want       string
+	}{
+		{"default", slo.DefaultDatasource, slo.DefaultDatasource.UID},
+		{"uid", slo.Datasource{Type: "prometheus", UID: "mimir"}, "mimir"},
+		{"variable preselecting a uid", slo.Datasource{Type: "prometheus", UID: "mimir", Variable: true}, "mimir"},
+		{"variable without a uid", slo.Datasource{Type: "prometheus", Variable: true}, ""},
+	}
+
+	for _, tt := range tests {
+		s := slo.NewAvailabilitySLO("api-availability", "API Availability", "", 28*slo.Day, 0.999,
+			`http_requests_total{code=~"5.."}`, "http_requests_total").WithDatasource(tt.datasource)
+
+		grafanaRules, err := GrafanaAlertRules(s, Options{})
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%s: got rules querying %q, want an error", tt.name, grafanaRules[0].Data[0].DatasourceUID)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		for _, rule := range grafanaRules {
+			if got := rule.Data[0].DatasourceUID; got != tt.want {
+				t.Errorf("%s: %s queries %q, want %q", tt.name, rule.Title, got, tt.want)
+			}
+		}
+	}
+}
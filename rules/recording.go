@@ -0,0 +1,45 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// RecordingGroup builds the slo:* recording rules the dashboard and alerts can read
+// instead of evaluating the raw selectors over long ranges
+func RecordingGroup(s slo.SLO) Group {
+	info := s.Info()
+	queries := s.Queries()
+	labels := map[string]string{"slo": info.UID}
+
+	rules := []Rule{
+		{Record: slo.ErrorRateRecord, Expr: queries.ErrorRateQuery(), Labels: labels},
+		{Record: slo.TotalRateRecord, Expr: queries.TotalRateQuery(), Labels: labels},
+	}
+
+	for _, window := range slo.RecordedWindows {
+		rules = append(rules, Rule{
+			Record: slo.ErrorRatioRecord(window),
+			Expr:   queries.ErrorRatioQuery(window),
+			Labels: labels,
+		})
+	}
+
+	// The ratio over the whole SLO window is weighted by traffic, like the dashboard's subqueries
+	if window := info.TimeWindow.String(); !slices.Contains(slo.RecordedWindows, window) {
+		rules = append(rules, Rule{
+			Record: slo.ErrorRatioRecord(window),
+			Expr: fmt.Sprintf("sum_over_time(%s[%s])\n/\nsum_over_time(%s[%s])",
+				slo.RecordedSelector(slo.ErrorRateRecord, info.UID), window,
+				slo.RecordedSelector(slo.TotalRateRecord, info.UID), window),
+			Labels: labels,
+		})
+	}
+
+	return Group{
+		Name:  "slo-" + info.UID + "-recordings",
+		Rules: rules,
+	}
+}
@@ -0,0 +1,33 @@
+package rules
+
+import "unobravo.com/go-obs-as-code/internal/yamlfile"
+
+// File is a Prometheus rule file, as loaded by rule_files or promtool
+type File struct {
+	Groups []Group `yaml:"groups"`
+}
+
+// Group is a set of rules evaluated together
+type Group struct {
+	Name     string `yaml:"name"`
+	Interval string `yaml:"interval,omitempty"`
+	Rules    []Rule `yaml:"rules"`
+}
+
+// Rule is either a recording rule (Record set) or an alerting rule (Alert set)
+type Rule struct {
+	Record      string            `yaml:"record,omitempty"`
+	Alert       string            `yaml:"alert,omitempty"`
+	Expr        string            `yaml:"expr"`
+	For         string            `yaml:"for,omitempty"`
+	Labels      map[string]string `yaml:"labels,omitempty"`
+	Annotations map[string]string `yaml:"annotations,omitempty"`
+}
+
+func NewFile(groups ...Group) *File {
+	return &File{Groups: groups}
+}
+
+func (f *File) ToYAML() (string, error) {
+	return yamlfile.Encode(f)
+}
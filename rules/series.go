@@ -0,0 +1,259 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+
+	"unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// EventSeries are two concrete series an SLI's selectors select, to feed it synthetic traffic
+type EventSeries struct {
+	// Counted is selected by the events the SLI counts, bad ones or good ones
+	Counted   promql.Selector
+	CountsBad bool
+
+	// Other is selected by the total. When Shared, the total selects Counted too
+	// and Other holds the remaining events, otherwise Other holds all of them.
+	Other  promql.Selector
+	Shared bool
+}
+
+func NewEventSeries(sli slo.SLI) (EventSeries, error) {
+	var events EventSeries
+	var counted, total string
+	switch sli := sli.(type) {
+	case *slo.BadEventsSLI:
+		counted, total, events.CountsBad = sli.Bad, sli.Total, true
+	case *slo.GoodEventsSLI:
+		counted, total = sli.Good, sli.Total
+	default:
+		return EventSeries{}, fmt.Errorf("no synthetic series for a %T SLI", sli)
+	}
+
+	countedSelector, err := promql.ParseSelector(counted)
+	if err != nil {
+		return EventSeries{}, fmt.Errorf("parsing %s: %w", counted, err)
+	}
+	totalSelector, err := promql.ParseSelector(total)
+	if err != nil {
+		return EventSeries{}, fmt.Errorf("parsing %s: %w", total, err)
+	}
+
+	if events.Counted, err = seriesMatching(countedSelector); err != nil {
+		return EventSeries{}, err
+	}
+	events.Shared = selects(totalSelector, events.Counted)
+	if events.Shared {
+		events.Other, err = seriesExcluding(totalSelector, countedSelector)
+	} else {
+		events.Other, err = seriesMatching(totalSelector)
+	}
+	return events, err
+}
+
+// PerMinute splits total events per minute, errorRatio of them bad, into the increase of each series
+func (e EventSeries) PerMinute(total, errorRatio float64) (counted, other float64) {
+	bad := total * errorRatio
+	counted = bad
+	if !e.CountsBad {
+		counted = total - bad
+	}
+	if e.Shared {
+		return counted, total - counted
+	}
+	return counted, total
+}
+
+// fillerValues are tried for labels whose matchers don't name a value, the empty one leaving the label out
+var fillerValues = []string{"", "0", "x", "other"}
+
+// seriesMatching is a concrete series, with one equality matcher per label, that selector selects
+func seriesMatching(selector promql.Selector) (promql.Selector, error) {
+	series := promql.NewSelector(selector.Metric)
+	for _, label := range matcherLabels(selector) {
+		matchers := labelMatchers(selector, label)
+		value, ok := findValue(candidates(matchers), func(v string) bool { return matchesAll(matchers, v) })
+		if !ok {
+			return promql.Selector{}, fmt.Errorf("no value of %s satisfies every matcher of %s", label, selector)
+		}
+		if value != "" {
+			series = series.With(promql.Equal(label, value))
+		}
+	}
+	return series, nil
+}
+
+// seriesExcluding is a series that include selects and exclude doesn't
+func seriesExcluding(include, exclude promql.Selector) (promql.Selector, error) {
+	series, err := seriesMatching(include)
+	if err != nil || series.Metric != exclude.Metric {
+		return series, err
+	}
+
+	// Break one matcher of exclude on a label include allows a different value for
+	for _, label := range matcherLabels(exclude) {
+		allowed := labelMatchers(include, label)
+		excluded := labelMatchers(exclude, label)
+		value, ok := findValue(candidates(slices.Concat(allowed, excluded)), func(v string) bool {
+			return matchesAll(allowed, v) && !matchesAll(excluded, v)
+		})
+		if !ok {
+			continue
+		}
+
+		matchers := slices.DeleteFunc(series.Matchers, func(m promql.Matcher) bool { return m.Label == label })
+		if value != "" {
+			matchers = append(matchers, promql.Equal(label, value))
+		}
+		return promql.NewSelector(series.Metric, matchers...), nil
+	}
+	return promql.Selector{}, fmt.Errorf("every series of %s is also selected by %s", include, exclude)
+}
+
+// selects reports whether selector selects the concrete series
+func selects(selector, series promql.Selector) bool {
+	if selector.Metric != series.Metric {
+		return false
+	}
+	for _, m := range selector.Matchers {
+		if !matches(m, seriesLabel(series, m.Label)) {
+			return false
+		}
+	}
+	return true
+}
+
+func seriesLabel(series promql.Selector, label string) string {
+	for _, m := range series.Matchers {
+		if m.Label == label {
+			return m.Value
+		}
+	}
+	return ""
+}
+
+func matcherLabels(selector promql.Selector) []string {
+	var labels []string
+	for _, m := range selector.Matchers {
+		if !slices.Contains(labels, m.Label) {
+			labels = append(labels, m.Label)
+		}
+	}
+	return labels
+}
+
+func labelMatchers(selector promql.Selector, label string) []promql.Matcher {
+	var matchers []promql.Matcher
+	for _, m := range selector.Matchers {
+		if m.Label == label {
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers
+}
+
+// candidates are the values worth trying for a label: those the matchers name or describe, then fillers
+func candidates(matchers []promql.Matcher) []string {
+	var values []string
+	for _, m := range matchers {
+		switch m.Type {
+		case promql.MatchEqual, promql.MatchNotEqual:
+			values = append(values, m.Value)
+		case promql.MatchRegexp, promql.MatchNotRegexp:
+			if sample, ok := regexpSample(m.Value); ok {
+				values = append(values, sample)
+			}
+		}
+	}
+	return append(values, fillerValues...)
+}
+
+func findValue(values []string, ok func(string) bool) (string, bool) {
+	for _, v := range values {
+		if ok(v) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+func matchesAll(matchers []promql.Matcher, value string) bool {
+	for _, m := range matchers {
+		if !matches(m, value) {
+			return false
+		}
+	}
+	return true
+}
+
+// matches applies a matcher to a label value, "" standing for a missing label as in Prometheus
+func matches(m promql.Matcher, value string) bool {
+	switch m.Type {
+	case promql.MatchEqual:
+		return value == m.Value
+	case promql.MatchNotEqual:
+		return value != m.Value
+	case promql.MatchRegexp, promql.MatchNotRegexp:
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return false
+		}
+		return re.MatchString(value) == (m.Type == promql.MatchRegexp)
+	}
+	return false
+}
+
+// regexpSample is one of the shortest strings the regular expression matches, e.g. 500 for 5..
+func regexpSample(expr string) (string, bool) {
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	var b strings.Builder
+	if !writeSample(&b, re.Simplify()) {
+		return "", false
+	}
+	sample := b.String()
+	return sample, matches(promql.Regexp("", expr), sample)
+}
+
+func writeSample(b *strings.Builder, re *syntax.Regexp) bool {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return false
+		}
+		b.WriteRune(re.Rune[0])
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte('0')
+	case syntax.OpCapture:
+		return writeSample(b, re.Sub[0])
+	case syntax.OpPlus:
+		return writeSample(b, re.Sub[0])
+	case syntax.OpRepeat:
+		for range re.Min {
+			if !writeSample(b, re.Sub[0]) {
+				return false
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if !writeSample(b, sub) {
+				return false
+			}
+		}
+	case syntax.OpAlternate:
+		return writeSample(b, re.Sub[0])
+	case syntax.OpNoMatch:
+		return false
+	}
+	// Empty matches, anchors, and the optional parts of star and quest add nothing
+	return true
+}
@@ -0,0 +1,416 @@
+package rules
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"unobravo.com/go-obs-as-code/internal/yamlfile"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// TestFile is a unit test file for promtool test rules
+type TestFile struct {
+	RuleFiles          []string   `yaml:"rule_files"`
+	EvaluationInterval string     `yaml:"evaluation_interval"`
+	Tests              []TestCase `yaml:"tests"`
+}
+
+// TestCase loads input series and checks which alerts fire at given times
+type TestCase struct {
+	Name          string          `yaml:"name"`
+	Interval      string          `yaml:"interval"`
+	InputSeries   []InputSeries   `yaml:"input_series"`
+	AlertRuleTest []AlertRuleTest `yaml:"alert_rule_test"`
+}
+
+// InputSeries is a series and its samples in expanding notation, e.g. 0+10x60
+type InputSeries struct {
+	Series string `yaml:"series"`
+	Values string `yaml:"values"`
+}
+
+// AlertRuleTest lists the alerts expected to fire at EvalTime, none if empty
+type AlertRuleTest struct {
+	EvalTime  string          `yaml:"eval_time"`
+	AlertName string          `yaml:"alertname"`
+	ExpAlerts []ExpectedAlert `yaml:"exp_alerts"`
+}
+
+type ExpectedAlert struct {
+	ExpLabels      map[string]string `yaml:"exp_labels"`
+	ExpAnnotations map[string]string `yaml:"exp_annotations"`
+}
+
+// testEventsPerMinute is the traffic of the input series, high enough for whole event counts to keep the error ratio
+const testEventsPerMinute = 100_000
+
+// testBurnMargin is how far below the lowest factor of an alert the silent scenario burns,
+// and how far above its factor the recovery scenario burns
+const testBurnMargin = 1.5
+
+// testPairMargin is how far above the factor of a window pair its firing scenario burns
+const testPairMargin = 1.2
+
+// testSlack is how far every window must clear or miss its factor, in the model of a scenario,
+// for the scenario to be generated: rate() extrapolation at the edges of a window must not change its outcome
+const testSlack = 1.1
+
+// AlertTests builds scenarios for every alert of the SLO's burn-rate policy. ruleFile is the path
+// of the rules, relative to the test file. Each alert gets:
+//   - for each window pair, a burn just above the pair's factor that fires the alert
+//   - a constant burn below every factor that keeps it silent
+//   - for each window pair, a spike over the short window that the long window keeps silent
+//   - a burn that fires the alert then stops, which the short window resets
+//
+// Scenarios the SLO's objective or policy make impossible, such as a factor above a 100% error ratio, are left out.
+func AlertTests(s slo.SLO, ruleFile string, opts Options) (*TestFile, error) {
+	info := s.Info()
+	group := AlertGroup(s, opts)
+
+	events, err := NewEventSeries(info.SLI)
+	if err != nil {
+		return nil, err
+	}
+
+	var tests []TestCase
+	for i, alert := range info.BurnRate {
+		if len(alert.Windows) == 0 {
+			continue
+		}
+		rule := group.Rules[i]
+		g := &scenarioGenerator{
+			info:     info,
+			alert:    alert,
+			events:   events,
+			expected: []ExpectedAlert{{ExpLabels: rule.Labels, ExpAnnotations: rule.Annotations}},
+		}
+		tests = append(tests, g.scenarios()...)
+	}
+
+	return &TestFile{
+		RuleFiles:          []string{ruleFile},
+		EvaluationInterval: "1m",
+		Tests:              tests,
+	}, nil
+}
+
+// scenarioGenerator builds the scenarios of a single alert
+type scenarioGenerator struct {
+	info     slo.Info
+	alert    slo.BurnRateAlert
+	events   EventSeries
+	expected []ExpectedAlert
+}
+
+func (g *scenarioGenerator) scenarios() []TestCase {
+	var tests []TestCase
+	for _, w := range g.alert.Windows {
+		if test, ok := g.pairFires(w); ok {
+			tests = append(tests, test)
+		}
+	}
+	if test, ok := g.silent(); ok {
+		tests = append(tests, test)
+	}
+	for _, w := range g.alert.Windows {
+		if test, ok := g.spikeSuppressed(w); ok {
+			tests = append(tests, test)
+		}
+	}
+	if test, ok := g.recovery(); ok {
+		tests = append(tests, test)
+	}
+	return tests
+}
+
+// pairFires burns just above the factor of w until the alert has been pending for its For period.
+// It prefers a scenario where no other pair of the alert holds, so that w alone fires the alert.
+func (g *scenarioGenerator) pairFires(w slo.BurnRateWindow) (TestCase, bool) {
+	burn := w.Factor(g.info.TimeWindow) * testPairMargin
+	others := slices.DeleteFunc(slices.Clone(g.alert.Windows), func(o slo.BurnRateWindow) bool { return o == w })
+	fires := func(in incident) bool { return g.holdsThroughFor(in, w, in.end()) }
+
+	in, ok := g.shortest(burn, g.durations(w), func(in incident) bool {
+		return fires(in) && g.silentThroughFor(in, in.end(), others)
+	})
+	if !ok {
+		in, ok = g.shortest(burn, g.durations(w), fires)
+	}
+	if !ok {
+		return TestCase{}, false
+	}
+
+	name := fmt.Sprintf("%s fires at a %sx burn rate over %s and %s", g.alert.Name, formatValue(in.burn), w.Long, w.Short)
+	return g.testCase(name, in, in.end(), alertCheck{at: in.end(), fires: true}), true
+}
+
+// silent burns below every factor of the alert from the start
+func (g *scenarioGenerator) silent() (TestCase, bool) {
+	burn := slices.Min(windowFactors(g.alert, g.info.TimeWindow)) / testBurnMargin
+	in := incident{burn: math.Round(burn*1e4) / 1e4, duration: g.round(g.alert.For + 10*time.Minute)}
+	if !g.silentThroughFor(in, in.end(), g.alert.Windows) {
+		return TestCase{}, false
+	}
+
+	name := fmt.Sprintf("%s stays silent at a %sx burn rate", g.alert.Name, formatValue(in.burn))
+	return g.testCase(name, in, in.end(), alertCheck{at: in.end()}), true
+}
+
+// spikeSuppressed burns over the short window of w for the alert's For period, too briefly for the long window to follow.
+// The burn is the geometric mean of the factor and of the burn that would clear the long window too.
+func (g *scenarioGenerator) spikeSuppressed(w slo.BurnRateWindow) (TestCase, bool) {
+	factor := w.Factor(g.info.TimeWindow)
+	duration := g.round(time.Duration(w.Short) + g.alert.For + time.Minute)
+	burn := min(factor*math.Sqrt(float64(w.Long)/float64(duration)), g.maxBurn())
+
+	in, ok := g.shortest(burn, []time.Duration{duration}, func(in incident) bool {
+		return in.burnOver(w.Short, in.end()) > factor*testSlack && g.silentThroughFor(in, in.end(), g.alert.Windows)
+	})
+	if !ok {
+		return TestCase{}, false
+	}
+
+	name := fmt.Sprintf("%s stays silent through a %s spike at a %sx burn rate, below its factor over %s",
+		g.alert.Name, FormatDuration(in.duration), formatValue(in.burn), w.Long)
+	return g.testCase(name, in, in.end(), alertCheck{at: in.end()}), true
+}
+
+// recovery fires the alert through the first window pair where it can, then stops burning:
+// the alert must resolve once the pair's short window is clean, while its long window still clears the factor
+func (g *scenarioGenerator) recovery() (TestCase, bool) {
+	for _, w := range g.alert.Windows {
+		factor := w.Factor(g.info.TimeWindow)
+		clean := g.round(time.Duration(w.Short) + time.Minute)
+
+		in, ok := g.shortest(min(factor*testBurnMargin, g.maxBurn()), g.durations(w), func(in incident) bool {
+			reset := in.end() + clean
+			return g.holdsThroughFor(in, w, in.end()) && g.silentThroughFor(in, reset, g.alert.Windows) &&
+				in.burnOver(w.Long, reset) > factor*testSlack
+		})
+		if !ok {
+			continue
+		}
+
+		name := fmt.Sprintf("%s resets %s after a %sx burn rate stops, once the %s window is clean",
+			g.alert.Name, FormatDuration(clean), formatValue(in.burn), w.Short)
+		return g.testCase(name, in, in.end()+clean, alertCheck{at: in.end(), fires: true}, alertCheck{at: in.end() + clean}), true
+	}
+	return TestCase{}, false
+}
+
+// shortest is the shortest incident burning at burn for one of the durations, after a clean history
+// from none to the alert's longest window, that ok accepts. promtool evaluates every rule at every
+// minute of a scenario, so a history only as long as the scenario needs keeps the tests fast.
+func (g *scenarioGenerator) shortest(burn float64, durations []time.Duration, ok func(incident) bool) (incident, bool) {
+	burn = math.Round(burn*1e4) / 1e4
+	if burn > g.maxBurn() {
+		return incident{}, false
+	}
+
+	var shortestWindow, longestWindow time.Duration
+	for i, w := range g.alert.Windows {
+		if i == 0 || time.Duration(w.Short) < shortestWindow {
+			shortestWindow = time.Duration(w.Short)
+		}
+		longestWindow = max(longestWindow, time.Duration(w.Long))
+	}
+	histories := append([]time.Duration{0}, g.spans(shortestWindow, longestWindow)...)
+
+	var best incident
+	found := false
+	for _, history := range histories {
+		for _, duration := range durations {
+			in := incident{start: history, duration: duration, burn: burn}
+			if (!found || in.end() < best.end()) && ok(in) {
+				best, found = in, true
+			}
+		}
+	}
+	return best, found
+}
+
+// durations a scenario burns for to clear the factor of w: from its short window and the For period,
+// until the whole long window is spent
+func (g *scenarioGenerator) durations(w slo.BurnRateWindow) []time.Duration {
+	return g.spans(time.Duration(w.Short)+g.alert.For, time.Duration(w.Long)+g.alert.For)
+}
+
+// spans doubles from from until to, both included and rounded up to whole samples
+func (g *scenarioGenerator) spans(from, to time.Duration) []time.Duration {
+	from = g.round(max(from, g.interval()))
+	to = g.round(max(from, to))
+
+	var spans []time.Duration
+	for d := from; d < to; d *= 2 {
+		spans = append(spans, d)
+	}
+	return append(spans, to)
+}
+
+// maxBurn is the burn rate of an error ratio of 1, rounded like the burn rates of the scenarios
+func (g *scenarioGenerator) maxBurn() float64 {
+	return math.Round(1e4/(1-g.info.Target)) / 1e4
+}
+
+// holdsThroughFor reports whether both windows of w clear its factor over the For period ending at t
+func (g *scenarioGenerator) holdsThroughFor(in incident, w slo.BurnRateWindow, t time.Duration) bool {
+	factor := w.Factor(g.info.TimeWindow) * testSlack
+	for at := t - g.alert.For; at <= t; at += g.interval() {
+		if in.burnOver(w.Long, at) <= factor || in.burnOver(w.Short, at) <= factor {
+			return false
+		}
+	}
+	return true
+}
+
+// silentThroughFor reports whether one window of each pair misses its factor over the For period ending at t,
+// so the pairs can't be firing the alert at t
+func (g *scenarioGenerator) silentThroughFor(in incident, t time.Duration, pairs []slo.BurnRateWindow) bool {
+	for at := t - g.alert.For; at <= t; at += g.interval() {
+		for _, w := range pairs {
+			factor := w.Factor(g.info.TimeWindow) / testSlack
+			if in.burnOver(w.Long, at) >= factor && in.burnOver(w.Short, at) >= factor {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// interval samples the input series every minute, or every 5 minutes when every short window is an hour or longer
+func (g *scenarioGenerator) interval() time.Duration {
+	for _, w := range g.alert.Windows {
+		if w.Short < slo.Hour {
+			return time.Minute
+		}
+	}
+	return 5 * time.Minute
+}
+
+// round rounds d up to a whole number of samples
+func (g *scenarioGenerator) round(d time.Duration) time.Duration {
+	interval := g.interval()
+	return (d + interval - 1) / interval * interval
+}
+
+// alertCheck expects the alert to be firing at a time, or not
+type alertCheck struct {
+	at    time.Duration
+	fires bool
+}
+
+// testCase loads the incident's series up to last and checks the alert at each given time
+func (g *scenarioGenerator) testCase(name string, in incident, last time.Duration, checks ...alertCheck) TestCase {
+	interval := g.interval()
+	segments := []segment{
+		{steps: int(in.start / interval)},
+		{steps: int(in.duration / interval), errorRatio: min(in.burn*(1-g.info.Target), 1)},
+		{steps: int((last - in.end()) / interval)},
+	}
+	perStep := testEventsPerMinute * float64(interval/time.Minute)
+
+	test := TestCase{
+		Name:        name,
+		Interval:    FormatDuration(interval),
+		InputSeries: incidentSeries(g.events, perStep, segments),
+	}
+	for _, check := range checks {
+		expected := []ExpectedAlert{}
+		if check.fires {
+			expected = g.expected
+		}
+		test.AlertRuleTest = append(test.AlertRuleTest, AlertRuleTest{
+			EvalTime:  FormatDuration(check.at),
+			AlertName: g.alert.Name,
+			ExpAlerts: expected,
+		})
+	}
+	return test
+}
+
+// incident is clean traffic until start, then a constant burn rate for duration, then clean traffic again
+type incident struct {
+	start    time.Duration
+	duration time.Duration
+	burn     float64
+}
+
+func (in incident) end() time.Duration {
+	return in.start + in.duration
+}
+
+// burnOver is the burn rate averaged over the window ending at t. Like rate(),
+// a window reaching before the first sample averages over the samples there are.
+func (in incident) burnOver(window slo.Window, t time.Duration) float64 {
+	span := min(time.Duration(window), t)
+	if span <= 0 {
+		return 0
+	}
+	overlap := min(t, in.end()) - max(t-span, in.start)
+	return in.burn * float64(max(overlap, 0)) / float64(span)
+}
+
+func windowFactors(alert slo.BurnRateAlert, timeWindow slo.Window) []float64 {
+	factors := make([]float64, 0, len(alert.Windows))
+	for _, w := range alert.Windows {
+		factors = append(factors, w.Factor(timeWindow))
+	}
+	return factors
+}
+
+// segment is a number of samples with a constant share of bad events
+type segment struct {
+	steps      int
+	errorRatio float64
+}
+
+// incidentSeries are counters matching the SLI's selectors, growing by perStep events at each sample
+// with the share of bad events of each segment in turn. Counts are whole, so that a segment picks up
+// exactly where promtool's additions left the previous one rather than looking like a counter reset.
+func incidentSeries(events EventSeries, perStep float64, segments []segment) []InputSeries {
+	var counted, other []string
+	var countedTotal, otherTotal float64
+	first := true
+	for _, seg := range segments {
+		if seg.steps == 0 {
+			continue
+		}
+		countedStep, otherStep := events.PerMinute(perStep, seg.errorRatio)
+		countedStep, otherStep = math.Round(countedStep), math.Round(otherStep)
+
+		// The first segment starts from 0, the next ones from the sample after the last one
+		steps := seg.steps
+		if first {
+			first = false
+		} else {
+			countedTotal += countedStep
+			otherTotal += otherStep
+			steps--
+		}
+		counted = append(counted, expanding(countedTotal, countedStep, steps))
+		other = append(other, expanding(otherTotal, otherStep, steps))
+		countedTotal += countedStep * float64(steps)
+		otherTotal += otherStep * float64(steps)
+	}
+	return []InputSeries{
+		{Series: events.Counted.String(), Values: strings.Join(counted, " ")},
+		{Series: events.Other.String(), Values: strings.Join(other, " ")},
+	}
+}
+
+// expanding renders steps+1 samples in promtool's expanding notation, e.g. 0+10x60
+func expanding(start, increment float64, steps int) string {
+	return formatValue(start) + "+" + formatValue(increment) + "x" + strconv.Itoa(steps)
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1e4)/1e4, 'f', -1, 64)
+}
+
+func (f *TestFile) ToYAML() (string, error) {
+	return yamlfile.Encode(f)
+}
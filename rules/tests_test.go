@@ -0,0 +1,169 @@
+package rules
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"unobravo.com/go-obs-as-code/internal/promtest"
+	"unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// TestAlertTests replays every generated scenario against the generated alert,
+// holding the condition through the alert's For period as promtool does
+func TestAlertTests(t *testing.T) {
+	latency := promql.NewHistogram("http_request_duration_seconds", promql.Equal("job", "api"))
+	slos := []slo.SLO{
+		// Errors and requests share a metric: the total is the 5xx series plus a non-5xx one
+		slo.NewAvailabilitySLO("api-availability", "API Availability", "",
+			slo.Week*4, 0.999, `http_requests_total{job="api", code=~"5.."}`, `http_requests_total{job="api"}`),
+		slo.NewThresholdLatencySLO("api-latency", "API", slo.Week*4, 0.95, latency, slo.Seconds, 250*time.Millisecond),
+	}
+
+	for _, s := range slos {
+		info := s.Info()
+		file, err := AlertTests(s, "rules.yaml", Options{})
+		if err != nil {
+			t.Fatalf("%s: %v", info.UID, err)
+		}
+
+		// A firing and a spike scenario per window pair, the silent one and the recovery
+		want := 0
+		for _, alert := range info.BurnRate {
+			want += 2*len(alert.Windows) + 2
+		}
+		if len(file.Tests) != want {
+			t.Errorf("%s: got %d scenarios, want %d", info.UID, len(file.Tests), want)
+		}
+
+		alerts := map[string]Rule{}
+		for _, rule := range AlertGroup(s, Options{}).Rules {
+			alerts[rule.Alert] = rule
+		}
+
+		for _, tc := range file.Tests {
+			t.Run(info.UID+"/"+tc.Name, func(t *testing.T) {
+				storage := loadTestCase(t, tc)
+
+				for _, check := range tc.AlertRuleTest {
+					rule := alerts[check.AlertName]
+					pending, _ := time.ParseDuration(rule.For)
+					evalTime, err := time.ParseDuration(check.EvalTime)
+					if err != nil {
+						t.Fatal(err)
+					}
+
+					firing := true
+					for at := max(evalTime-pending, 0); at <= evalTime; at += time.Minute {
+						firing = firing && len(storage.Eval(rule.Expr, at)) > 0
+					}
+					if want := len(check.ExpAlerts) > 0; firing != want {
+						t.Errorf("%s firing at %s = %t, want %t", check.AlertName, check.EvalTime, firing, want)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestAlertTestsExercisePairs checks that the firing scenario of each window pair holds that pair,
+// and that the primary pair of the fast burn fires on its own, without the 6h window
+func TestAlertTestsExercisePairs(t *testing.T) {
+	s := slo.NewAvailabilitySLO("api-availability", "API Availability", "",
+		slo.Week*4, 0.999, `http_requests_total{job="api", code=~"5.."}`, `http_requests_total{job="api"}`)
+	info := s.Info()
+	file, err := AlertTests(s, "rules.yaml", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, alert := range info.BurnRate {
+		for i, w := range alert.Windows {
+			suffix := fmt.Sprintf(" burn rate over %s and %s", w.Long, w.Short)
+			n := slices.IndexFunc(file.Tests, func(tc TestCase) bool {
+				return strings.HasPrefix(tc.Name, alert.Name+" fires") && strings.HasSuffix(tc.Name, suffix)
+			})
+			if n < 0 {
+				t.Errorf("%s: no firing scenario over %s and %s", alert.Name, w.Long, w.Short)
+				continue
+			}
+			tc := file.Tests[n]
+			storage := loadTestCase(t, tc)
+			evalTime, _ := time.ParseDuration(tc.AlertRuleTest[0].EvalTime)
+
+			holding := map[int]bool{}
+			for j, other := range alert.Windows {
+				pair := slo.BurnRateAlert{Windows: []slo.BurnRateWindow{other}}
+				holding[j] = len(storage.Eval(s.Queries().BurnRateConditionQuery(pair), evalTime)) > 0
+			}
+			if !holding[i] {
+				t.Errorf("%s: the pair over %s and %s doesn't hold in its own scenario", tc.Name, w.Long, w.Short)
+			}
+			if alert.Name == "SLOFastBurn" && i == 0 && holding[1] {
+				t.Errorf("%s: the 6h pair holds too, the 1h pair isn't tested on its own", tc.Name)
+			}
+		}
+	}
+}
+
+func loadTestCase(t *testing.T, tc TestCase) *promtest.Storage {
+	t.Helper()
+	var input strings.Builder
+	input.WriteString("load " + tc.Interval + "\n")
+	for _, series := range tc.InputSeries {
+		input.WriteString("  " + series.Series + " " + series.Values + "\n")
+	}
+	return promtest.Load(t, input.String())
+}
+
+func TestSeriesExcluding(t *testing.T) {
+	tests := []struct {
+		include, exclude string
+		want             string
+	}{
+		{`http_requests_total{job="api"}`, `http_requests_total{job="api", code=~"5.."}`, `http_requests_total{job="api"}`},
+		{`http_requests_total{job="api"}`, `http_requests_total{job="api", code!~"2..|3.."}`, `http_requests_total{job="api", code="200"}`},
+		{`http_requests_total{job="api"}`, `http_requests_total{job="api", code!="200"}`, `http_requests_total{job="api", code="200"}`},
+		{`requests_total{job="api"}`, `errors_total{job="api"}`, `requests_total{job="api"}`},
+	}
+
+	for _, tt := range tests {
+		include, _ := promql.ParseSelector(tt.include)
+		exclude, _ := promql.ParseSelector(tt.exclude)
+		got, err := seriesExcluding(include, exclude)
+		if err != nil {
+			t.Errorf("seriesExcluding(%s, %s): %v", tt.include, tt.exclude, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("seriesExcluding(%s, %s) = %s, want %s", tt.include, tt.exclude, got, tt.want)
+		}
+		if !selects(include, got) || selects(exclude, got) {
+			t.Errorf("seriesExcluding(%s, %s) = %s, selected by the wrong side", tt.include, tt.exclude, got)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0m"},
+		{30 * time.Second, "30s"},
+		{2 * time.Minute, "2m"},
+		{90 * time.Second, "1m30s"},
+		{2 * time.Hour, "2h"},
+		{65 * time.Minute, "1h5m"},
+		{30 * time.Hour, "30h"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -12,15 +12,21 @@ type AvailabilitySLO struct {
 	UID                string
 	Name               string
 	Description        string
-	TimeWindow         string
+	TimeWindow         Window
 	Target             float64
 	SuccessMetricQuery string
 	TotalMetricQuery   string
+	Labels             map[string]string
+	Datasource         Datasource
+	BurnRatePolicy     BurnRatePolicy
+	CreatedAt          time.Time
+	Variables          []Variable
 	dashboard          *Dashboard
-	queries            *AvailabilityQueries
+	queries            *SLIQueries
+	templated          *SLIQueries
 }
 
-func NewAvailabilitySLO(uid, name, description, timeWindow string, target float64, successMetricQuery, totalMetricQuery string) *AvailabilitySLO {
+func NewAvailabilitySLO(uid, name, description string, timeWindow Window, target float64, successMetricQuery, totalMetricQuery string) *AvailabilitySLO {
 	slo := &AvailabilitySLO{
 		UID:                uid,
 		Name:               name,
@@ -29,102 +35,134 @@ func NewAvailabilitySLO(uid, name, description, timeWindow string, target float6
 		Target:             target,
 		SuccessMetricQuery: successMetricQuery,
 		TotalMetricQuery:   totalMetricQuery,
-		dashboard:          NewDashboard(uid, name, description),
-		queries:            NewAvailabilityQueries(successMetricQuery, totalMetricQuery, target, timeWindow),
+		Datasource:         DefaultDatasource,
+		BurnRatePolicy:     DefaultBurnRatePolicy,
+		queries:            NewSLIQueries(NewBadEventsSLI(successMetricQuery, totalMetricQuery), target, timeWindow),
 	}
 
-	slo.buildRecapRow()
-	slo.buildSliRow()
-	slo.buildErrorBudgetRow()
-	slo.buildBurnRateRow()
-	slo.buildEventRateRow()
+	return slo
+}
+
+// WithLabels attaches labels that identify the SLO in generated alerts
+func (slo *AvailabilitySLO) WithLabels(labels map[string]string) *AvailabilitySLO {
+	slo.Labels = labels
+	return slo
+}
+
+func (slo *AvailabilitySLO) Info() Info {
+	return Info{
+		UID:         slo.UID,
+		Name:        slo.Name,
+		Description: slo.Description,
+		Kind:        "availability",
+		TimeWindow:  slo.TimeWindow,
+		Target:      slo.Target,
+		SLI:         slo.queries.SLI,
+		Labels:      slo.Labels,
+		Datasource:  slo.Datasource,
+		BurnRate:    slo.BurnRatePolicy,
+	}
+}
+
+// WithBurnRatePolicy replaces the default burn-rate alerts shown on the dashboard
+func (slo *AvailabilitySLO) WithBurnRatePolicy(policy BurnRatePolicy) *AvailabilitySLO {
+	slo.BurnRatePolicy = policy
+	return slo
+}
 
+// WithCreatedAt records when the SLO was created, so the dashboard can tell the data from before apart
+func (slo *AvailabilitySLO) WithCreatedAt(createdAt time.Time) *AvailabilitySLO {
+	slo.CreatedAt = createdAt
 	return slo
 }
 
+// beforeCreation keeps the points of expr older than the SLO
+func (slo *AvailabilitySLO) beforeCreation(expr string) string {
+	return fmt.Sprintf(`%s AND timestamp(%s) < %d`, expr, slo.panelQueries().EventRateQuery(), slo.CreatedAt.Unix())
+}
+
+// WithDatasource sets the datasource the dashboard panels query
+func (slo *AvailabilitySLO) WithDatasource(ds Datasource) *AvailabilitySLO {
+	slo.Datasource = ds
+	return slo
+}
+
+// WithRecordingRules makes the dashboard and alerts read the SLO's recording rules
+// instead of evaluating the raw selectors
+func (slo *AvailabilitySLO) WithRecordingRules() *AvailabilitySLO {
+	slo.queries.UseRecordingRules(slo.UID)
+	return slo
+}
+
+func (slo *AvailabilitySLO) Queries() Queries {
+	return slo.queries
+}
+
+// WithTemplating makes the dashboard query sli, whose selectors reference the variables,
+// while alerts and recording rules keep the SLO's own selectors
+func (slo *AvailabilitySLO) WithTemplating(sli SLI, variables ...Variable) *AvailabilitySLO {
+	slo.Variables = variables
+	slo.templated = NewSLIQueries(sli, slo.Target, slo.TimeWindow)
+	return slo
+}
+
+// panelQueries are the queries of the dashboard panels
+func (slo *AvailabilitySLO) panelQueries() *SLIQueries {
+	if slo.templated != nil {
+		return slo.templated
+	}
+	return slo.queries
+}
+
 func (slo *AvailabilitySLO) BuildJSON() (string, error) {
+	slo.dashboard = NewDashboard(slo.UID, slo.Name, slo.Description)
+	if slo.Datasource.Variable {
+		slo.dashboard.WithDatasourceVariable(datasourceVariable, slo.Datasource.Type, slo.Datasource.UID)
+	}
+	for _, variable := range slo.Variables {
+		slo.dashboard.WithVariable(variable)
+	}
+
+	layout := components.NewLayout()
+	slo.buildRecapRow(layout.Row("").WithHeight(4))
+	slo.buildSliRow(layout.Row("").WithHeight(7))
+	slo.buildErrorBudgetRow(layout.Row("").WithHeight(7))
+	slo.buildBurnRateRow(layout.Row("").WithHeight(7))
+	slo.buildEventRateRow(layout.Row("").WithHeight(7))
+	slo.dashboard.WithLayout(layout)
+
 	return slo.dashboard.ToJSON()
 }
 
 // buildRecapRow builds the first row with recap information
-func (slo *AvailabilitySLO) buildRecapRow() {
+func (slo *AvailabilitySLO) buildRecapRow(row *components.Row) {
 	// Text panel with title
-	textPanel := components.NewTextPanel("", "# "+slo.Name, dashboard.GridPos{H: 4, W: 7, X: 0, Y: 0})
-	slo.dashboard.WithPanel(textPanel)
+	textPanel := components.NewTextPanel("", "# "+slo.Name, dashboard.GridPos{})
+	row.WithPanel(textPanel, components.Columns(7))
 
-	// Fast burn rate alert panel
+	// Burn rate alert panels, sharing the 8 columns after the title
 	prometheusDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("short"),
 		Decimals: float64Ptr(0),
 	}
-
-	fastBurnTarget := components.NewPrometheusQuery("fast_burn_alert", slo.queries.FastBurnRateQuery())
-	fastBurnPanel := components.NewStatPanel(
-		"🚨 Fast Burn Rate Alert",
-		"Critical alert when burn rate exceeds fast burn thresholds:\n• 14.4x for 5min AND 1hour\n• 6x for 30min AND 6hour",
-		dashboard.GridPos{H: 4, W: 4, X: 7, Y: 0},
-	).WithDatasource(prometheusDS).
-		WithTarget(fastBurnTarget).
-		WithMappings([]dashboard.ValueMapping{
-			{
-				ValueMap: &dashboard.ValueMap{
-					Type: dashboard.MappingTypeValueToText,
-					Options: map[string]dashboard.ValueMappingResult{
-						"0": {
-							Text:  stringPtr("OK"),
-							Color: stringPtr("green"),
-						},
-						"1": {
-							Text:  stringPtr("FIRING"),
-							Color: stringPtr("red"),
-						},
-					},
-				},
-			}})
-	slo.dashboard.WithPanel(fastBurnPanel)
-
-	// Slow burn rate alert panel
-	slowBurnTarget := components.NewPrometheusQuery("slow_burn_alert", slo.queries.SlowBurnRateQuery())
-	slowBurnPanel := components.NewStatPanel(
-		"⚠️ Slow Burn Rate Alert",
-		"Warning alert for slow burn rate:\n• 3x for 2hours AND 24hours\n• 1x for 6hours AND 72hours",
-		dashboard.GridPos{H: 4, W: 4, X: 11, Y: 0},
-	).WithDatasource(prometheusDS).
-		WithTarget(slowBurnTarget).
-		WithMappings([]dashboard.ValueMapping{
-			{
-				ValueMap: &dashboard.ValueMap{
-					Type: dashboard.MappingTypeValueToText,
-					Options: map[string]dashboard.ValueMappingResult{
-						"0": {
-							Text:  stringPtr("OK"),
-							Color: stringPtr("green"),
-						},
-						"1": {
-							Text:  stringPtr("CRITICAL"),
-							Color: stringPtr("red"),
-						},
-					},
-				},
-			}})
-	slo.dashboard.WithPanel(slowBurnPanel)
+	burnRateAlertPanels(row, slo.BurnRatePolicy, slo.panelQueries(), slo.TimeWindow, prometheusDS, 8)
 
 	// Time window panel
 	timeWindowDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 		Min:  float64Ptr(0),
 		Max:  float64Ptr(1),
 	}
 
-	timeWindowTarget := components.NewPrometheusQuery("time_window", slo.queries.TimeWindowQuery())
+	timeWindowTarget := components.NewPrometheusQuery("time_window", slo.panelQueries().TimeWindowQuery())
 	timeWindowPanel := components.NewStatPanel(
 		"Time Window",
 		"The time window over which the service level objective is being measured over",
-		dashboard.GridPos{H: 4, W: 4, X: 15, Y: 0},
+		dashboard.GridPos{},
 	).WithDatasource(timeWindowDS).
 		WithTarget(timeWindowTarget).
 		WithTransformations([]dashboard.DataTransformerConfig{
@@ -154,52 +192,44 @@ func (slo *AvailabilitySLO) buildRecapRow() {
 		},
 	})
 
-	slo.dashboard.WithPanel(timeWindowPanel)
+	row.WithPanel(timeWindowPanel, components.Columns(4))
 
 	// SLO target panel
 	sloTargetDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("percentunit"),
 		Decimals: float64Ptr(2),
 		Min:      float64Ptr(0),
 		Max:      float64Ptr(1),
 	}
 
-	sloTarget := components.NewPrometheusQuery("A", slo.queries.SLOTargetQuery())
+	sloTarget := components.NewPrometheusQuery("A", slo.panelQueries().SLOTargetQuery())
 	sloPanel := components.NewStatPanel(
 		"SLO",
 		"The SLO's Objective value. Always between 0 and 100%",
-		dashboard.GridPos{H: 4, W: 5, X: 19, Y: 0},
+		dashboard.GridPos{},
 	).WithDatasource(sloTargetDS).WithTarget(sloTarget)
-	slo.dashboard.WithPanel(sloPanel)
+	row.WithPanel(sloPanel, components.Columns(5))
 }
 
 // SLI row
-func (slo *AvailabilitySLO) buildSliRow() {
+func (slo *AvailabilitySLO) buildSliRow(row *components.Row) {
 	// SLI timeseries panel
 	sliDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 	}
 
-	availabilitySLIAvgQuery := fmt.Sprintf(`avg_over_time((
-      (((sum(rate(%s[$__rate_interval] offset 2m)) - sum(rate(%s[$__rate_interval] offset 2m) or vector(0))) or 0 * sum(rate(%s[$__rate_interval] offset 2m))) / (sum(rate(%s[$__rate_interval] offset 2m))))
-    )[$__interval:])`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery, slo.TotalMetricQuery)
-	sliTarget1 := components.NewPrometheusQuery("custom_sli_avg", availabilitySLIAvgQuery).WithLegend("AVG")
-
-	futureTimestamp := fmt.Sprintf("%d", time.Now().Unix())
-	sliTargetExpr := fmt.Sprintf(`(((sum(rate(%s[$__rate_interval] offset 2m)) - sum(rate(%s[$__rate_interval] offset 2m) or vector(0))) or 0 * sum(rate(%s[$__rate_interval] offset 2m))) / (sum(rate(%s[$__rate_interval] offset 2m)))) AND timestamp(sum(rate(%s[$__rate_interval] offset 2m))) < %s`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery, slo.TotalMetricQuery, slo.TotalMetricQuery, futureTimestamp)
-	sliTarget2 := components.NewPrometheusQuery("computed_before_creation_time", sliTargetExpr).WithLegend("Before Creation Time")
+	sliTarget := components.NewPrometheusQuery("custom_sli_avg", slo.panelQueries().SLIQuery()).WithLegend("AVG")
 
 	sliPanel := components.NewTimeSeriesPanel(
 		"SLI",
 		"Service level indicator",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 4},
+		dashboard.GridPos{},
 	).WithDatasource(sliDS).
-		WithTarget(sliTarget1).
-		WithTarget(sliTarget2).
+		WithTarget(sliTarget).
 		WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 			{
 				Color: "red",
@@ -210,29 +240,27 @@ func (slo *AvailabilitySLO) buildSliRow() {
 				Value: float64Ptr(slo.Target),
 			},
 		})
-	slo.dashboard.WithPanel(sliPanel)
+	if !slo.CreatedAt.IsZero() {
+		sliPanel.WithTarget(components.NewPrometheusQuery("computed_before_creation_time", slo.beforeCreation(slo.panelQueries().SLIQuery())).WithLegend("Before Creation Time"))
+	}
+	row.WithPanel(sliPanel, components.Columns(19))
 
-	// SLI 28d stat panel
-	sli28dDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+	// SLI stat panel over the whole window
+	sliWindowDS := &components.DatasourceConfig{
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("percentunit"),
 		Decimals: float64Ptr(1),
 		Min:      float64Ptr(0),
 		Max:      float64Ptr(1),
 	}
 
-	availability28dQuery := fmt.Sprintf(`(
-      sum_over_time((sum(rate(%s[5m])) - sum(rate(%s[5m]) or vector(0)))[28d:5m])
-      /
-      sum_over_time((sum(rate(%s[5m])))[28d:5m])
-    )`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery)
-	sli28dTarget := components.NewPrometheusQuery("custom_sli_28d", availability28dQuery).WithInterval("1m")
-	sli28dPanel := components.NewStatPanel(
-		"SLI (last 28d)",
-		"Service level indicator's value over the last 28d",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 4},
-	).WithDatasource(sli28dDS).WithTarget(sli28dTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
+	sliWindowTarget := components.NewPrometheusQuery("custom_sli_window", slo.panelQueries().SLITimeWindowQuery()).WithInterval("1m")
+	sliWindowPanel := components.NewStatPanel(
+		fmt.Sprintf("SLI (last %s)", slo.TimeWindow),
+		fmt.Sprintf("Service level indicator's value over the last %s", slo.TimeWindow.Describe()),
+		dashboard.GridPos{},
+	).WithDatasource(sliWindowDS).WithTarget(sliWindowTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "red",
 			Value: float64Ptr(0),
@@ -242,122 +270,106 @@ func (slo *AvailabilitySLO) buildSliRow() {
 			Value: float64Ptr(slo.Target),
 		},
 	})
-	slo.dashboard.WithPanel(sli28dPanel)
+	row.WithPanel(sliWindowPanel, components.Columns(5))
 }
 
 // Error budget row
-func (slo *AvailabilitySLO) buildErrorBudgetRow() {
+func (slo *AvailabilitySLO) buildErrorBudgetRow(row *components.Row) {
 	// Error budget trend timeseries
 	budgetDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 	}
 
-	budgetTrendQuery := fmt.Sprintf(`(
-    (
-      sum_over_time((sum(rate(%s[5m])) - sum(rate(%s[5m]) or vector(0)))[28d:5m])
-      /
-      sum_over_time((sum(rate(%s[5m])))[28d:5m])
-    ) - %f
-  ) / (1 - %f)`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery, slo.Target, slo.Target)
-	budgetTrendTarget := components.NewPrometheusQuery("custom_error_budget_trend", budgetTrendQuery).WithLegend("Error Budget")
+	budgetTrendTarget := components.NewPrometheusQuery("custom_error_budget_trend", slo.panelQueries().ErrorBudgetTrendQuery()).WithLegend("Error Budget")
 	budgetTrendPanel := components.NewTimeSeriesPanel(
 		"Error Budget Trend",
 		"If error budget is decreasing over time, it means that your service is spending its error budget faster than it's earning it back.\n\nIf error budget is increasing over time, you're not spending too much of your error budget.",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 11},
+		dashboard.GridPos{},
 	).WithDatasource(budgetDS).WithTarget(budgetTrendTarget)
-	slo.dashboard.WithPanel(budgetTrendPanel)
+	row.WithPanel(budgetTrendPanel, components.Columns(19))
 
 	// Remaining error budget stat
 	remainingBudgetDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 		Min:  float64Ptr(1),
 		Max:  float64Ptr(1),
 	}
 
-	remainingBudgetTarget := components.NewPrometheusQuery("custom_remaining_error_budget", budgetTrendQuery)
+	remainingBudgetTarget := components.NewPrometheusQuery("custom_remaining_error_budget", slo.panelQueries().RemainingErrorBudgetQuery())
 	remainingBudgetPanel := components.NewStatPanel(
 		"Remaining Error Budget",
-		"The unspent error budget over the last 28d window",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 11},
+		fmt.Sprintf("The unspent error budget over the last %s window", slo.TimeWindow),
+		dashboard.GridPos{},
 	).WithDatasource(remainingBudgetDS).WithTarget(remainingBudgetTarget)
-	slo.dashboard.WithPanel(remainingBudgetPanel)
+	row.WithPanel(remainingBudgetPanel, components.Columns(5))
 }
 
 // The burn rate row
-func (slo *AvailabilitySLO) buildBurnRateRow() {
+func (slo *AvailabilitySLO) buildBurnRateRow(row *components.Row) {
 	// Burn rate timeseries
 	burnRateDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("none"),
 	}
 
 	// Target 1: AVG
-	burnRateAvgQuery := fmt.Sprintf(`(1 - avg_over_time((
-      (((sum(rate(%s[5m])) - sum(rate(%s[5m]) or vector(0))) or 0 * sum(rate(%s[5m]))) / (sum(rate(%s[5m]))))
-    )[$__interval:])) / (1 - %f)`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery, slo.TotalMetricQuery, slo.Target)
-	burnRateTarget1 := components.NewPrometheusQuery("custom_burn_rate_avg", burnRateAvgQuery).WithLegend("AVG")
+	burnRateTarget1 := components.NewPrometheusQuery("custom_burn_rate_avg", slo.panelQueries().BurnRateQuery()).WithLegend("AVG")
 
 	// Target 2: Instant
-	burnRateInstantQuery := fmt.Sprintf(`(1 - (
-      (((sum(rate(%s[5m])) - sum(rate(%s[5m]) or vector(0))) or 0 * sum(rate(%s[5m]))) / (sum(rate(%s[5m]))))
-    )) / (1 - %f)`, slo.TotalMetricQuery, slo.SuccessMetricQuery, slo.TotalMetricQuery, slo.TotalMetricQuery, slo.Target)
-	burnRateTarget2 := components.NewPrometheusQuery("custom_burn_rate_instant", burnRateInstantQuery).WithLegend("Instant")
+	burnRateTarget2 := components.NewPrometheusQuery("custom_burn_rate_instant", slo.panelQueries().InstantBurnRateQuery()).WithLegend("Instant")
 
 	burnRatePanel := components.NewTimeSeriesPanel(
 		"Error Budget Burn Rate",
 		"The burn rate is the rate that this SLO is spending its error budget over last 5 min [0, 1.0]. A 1x burn rate will consume the entire error budget allotted for that period.",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 18},
+		dashboard.GridPos{},
 	).WithDatasource(burnRateDS).
 		WithTarget(burnRateTarget1).
 		WithTarget(burnRateTarget2)
-	slo.dashboard.WithPanel(burnRatePanel)
+	row.WithPanel(burnRatePanel, components.Columns(19))
 
 	// Current burn rate
 	currentBurnDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("none"),
 		Decimals: float64Ptr(2),
 	}
 
-	currentBurnTarget := components.NewPrometheusQuery("custom_current_burn_rate", burnRateAvgQuery)
+	currentBurnTarget := components.NewPrometheusQuery("custom_current_burn_rate", slo.panelQueries().BurnRateQuery())
 	currentBurnPanel := components.NewStatPanel(
 		"Current Burn Rate",
 		"The burn rate is the rate that this SLO is spending its error budget over last 5 min [0, 1.0]. A 1x burn rate will consume the entire error budget allotted for that period.",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 18},
+		dashboard.GridPos{},
 	).WithDatasource(currentBurnDS).WithTarget(currentBurnTarget)
-	slo.dashboard.WithPanel(currentBurnPanel)
+	row.WithPanel(currentBurnPanel, components.Columns(5))
 }
 
 // The event rate row
-func (slo *AvailabilitySLO) buildEventRateRow() {
+func (slo *AvailabilitySLO) buildEventRateRow(row *components.Row) {
 	// Event rate timeseries
 	eventRateDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("reqps"),
 	}
 
-	// Target 1: AVG
-	eventRateTarget1 := components.NewPrometheusQuery("custom_event_rate", fmt.Sprintf(`sum(rate(%s[$__rate_interval] offset 2m))`, slo.TotalMetricQuery)).WithLegend("AVG")
-
-	// Target 2: Before Creation
-	futureTimestamp := fmt.Sprintf("%d", time.Now().Unix())
-	eventRateTarget2 := components.NewPrometheusQuery("custom_event_rate_historical", fmt.Sprintf(`sum(rate(%s[$__rate_interval] offset 2m)) AND timestamp(sum(rate(%s[$__rate_interval] offset 2m))) < %s`, slo.TotalMetricQuery, slo.TotalMetricQuery, futureTimestamp)).WithLegend("Before Creation")
+	eventRateTarget := components.NewPrometheusQuery("custom_event_rate", slo.panelQueries().EventRateQuery()).WithLegend("AVG")
 
 	eventRatePanel := components.NewTimeSeriesPanel(
 		"Event Rate",
 		"Total Rate (for SLIs that compare rate of successful events to rate of total events, this is the latter)",
-		dashboard.GridPos{H: 7, W: 24, X: 0, Y: 25},
+		dashboard.GridPos{},
 	).WithDatasource(eventRateDS).
-		WithTarget(eventRateTarget1).
-		WithTarget(eventRateTarget2)
-	slo.dashboard.WithPanel(eventRatePanel)
+		WithTarget(eventRateTarget)
+	if !slo.CreatedAt.IsZero() {
+		eventRatePanel.WithTarget(components.NewPrometheusQuery("custom_event_rate_historical", slo.beforeCreation(slo.panelQueries().EventRateQuery())).WithLegend("Before Creation"))
+	}
+	row.WithPanel(eventRatePanel, components.Full)
 }
 
 func float64Ptr(f float64) *float64 {
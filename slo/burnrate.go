@@ -0,0 +1,140 @@
+package slo
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+	"unobravo.com/go-obs-as-code/components"
+)
+
+// BurnRateWindow fires when the burn rate exceeds its factor over both windows.
+// BudgetConsumed is the share of the whole error budget spent over Long by then,
+// the short window only makes the alert reset quickly once the burn stops.
+type BurnRateWindow struct {
+	Short          Window
+	Long           Window
+	BudgetConsumed float64
+}
+
+// Factor is the burn rate that spends BudgetConsumed of the error budget of a timeWindow SLO over Long
+func (w BurnRateWindow) Factor(timeWindow Window) float64 {
+	factor := w.BudgetConsumed * float64(timeWindow) / float64(w.Long)
+	return math.Round(factor*1e4) / 1e4
+}
+
+// BurnRateAlert is a multi-window burn-rate alert, firing when any of its windows does
+type BurnRateAlert struct {
+	Name     string
+	Title    string
+	Severity string
+	For      time.Duration
+	Windows  []BurnRateWindow
+}
+
+// BurnRatePolicy is the list of burn-rate alerts of an SLO, shown on its dashboard and exported as rules
+type BurnRatePolicy []BurnRateAlert
+
+// DefaultBurnRatePolicy pages on 2% of the budget spent in an hour or 5% in 6 hours,
+// and opens a ticket on 10% spent in a day or in 3 days
+var DefaultBurnRatePolicy = BurnRatePolicy{
+	{
+		Name:     "SLOFastBurn",
+		Title:    "🚨 Fast Burn Rate Alert",
+		Severity: "page",
+		For:      2 * time.Minute,
+		Windows: []BurnRateWindow{
+			{Short: 5 * Minute, Long: Hour, BudgetConsumed: 0.02},
+			{Short: 30 * Minute, Long: 6 * Hour, BudgetConsumed: 0.05},
+		},
+	},
+	{
+		Name:     "SLOSlowBurn",
+		Title:    "⚠️ Slow Burn Rate Alert",
+		Severity: "ticket",
+		For:      15 * time.Minute,
+		Windows: []BurnRateWindow{
+			{Short: 2 * Hour, Long: Day, BudgetConsumed: 0.1},
+			{Short: 6 * Hour, Long: 3 * Day, BudgetConsumed: 0.1},
+		},
+	},
+}
+
+// Describe lists the factor and windows of every condition of the alert, one per line
+func (a BurnRateAlert) Describe(timeWindow Window) string {
+	lines := make([]string, 0, len(a.Windows))
+	for _, w := range a.Windows {
+		lines = append(lines, fmt.Sprintf("• %sx for %s AND %s (%s of the budget)",
+			FormatFactor(w.Factor(timeWindow)), w.Short, w.Long, FormatPercent(w.BudgetConsumed)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BurnRateCondition is the expression of a multi-window burn-rate alert: it holds while the burn rate
+// exceeds the factor of any of the alert's windows over both the long and the short window.
+// Prometheus alerts, dashboard panels and Grafana-managed rules all evaluate it.
+func BurnRateCondition(burnRate func(window string) string, alert BurnRateAlert, timeWindow Window) string {
+	conditions := make([]string, 0, len(alert.Windows))
+	for _, w := range alert.Windows {
+		factor := FormatFactor(w.Factor(timeWindow))
+		conditions = append(conditions, fmt.Sprintf("(\n  %s > %s\n  and\n  %s > %s\n)",
+			burnRate(w.Long.String()), factor, burnRate(w.Short.String()), factor))
+	}
+	return strings.Join(conditions, "\nor\n")
+}
+
+// burnRateAlertQuery is 1 while the alert condition holds and 0 otherwise
+func burnRateAlertQuery(burnRate func(window string) string, alert BurnRateAlert, timeWindow Window) string {
+	return "(max(" + BurnRateCondition(burnRate, alert, timeWindow) + ") > bool 0) or vector(0)"
+}
+
+// BurnRateAlertPanelID is the panel ID of the stat panel showing the i-th alert of the policy,
+// fixed so that alert rules can link to it
+func BurnRateAlertPanelID(i int) uint32 {
+	return burnRateAlertPanelIDs + uint32(i)
+}
+
+// burnRateAlertPanelIDs leaves room for Grafana to number the other panels below it
+const burnRateAlertPanelIDs = 100
+
+// burnRateAlertPanels adds one stat panel per alert to the row, splitting columns between them
+func burnRateAlertPanels(row *components.Row, policy BurnRatePolicy, queries Queries, timeWindow Window, ds *components.DatasourceConfig, columns uint32) {
+	n := uint32(len(policy))
+	for i, alert := range policy {
+		title := alert.Title
+		if title == "" {
+			title = alert.Name
+		}
+
+		target := components.NewPrometheusQuery(fmt.Sprintf("burn_alert_%d", i), queries.BurnRateAlertQuery(alert))
+		panel := components.NewStatPanel(
+			title,
+			fmt.Sprintf("Fires with severity %s when the burn rate exceeds:\n%s", alert.Severity, alert.Describe(timeWindow)),
+			dashboard.GridPos{},
+		).WithID(BurnRateAlertPanelID(i)).
+			WithDatasource(ds).
+			WithTarget(target).
+			WithMappings([]dashboard.ValueMapping{
+				{
+					ValueMap: &dashboard.ValueMap{
+						Type: dashboard.MappingTypeValueToText,
+						Options: map[string]dashboard.ValueMappingResult{
+							"0": {
+								Text:  stringPtr("OK"),
+								Color: stringPtr("green"),
+							},
+							"1": {
+								Text:  stringPtr("FIRING"),
+								Color: stringPtr("red"),
+							},
+						},
+					},
+				}})
+
+		// Spread the remainder so the panels fill the columns exactly
+		width := columns*uint32(i+1)/n - columns*uint32(i)/n
+		row.WithPanel(panel, components.Columns(max(width, 1)))
+	}
+}
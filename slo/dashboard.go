@@ -2,17 +2,27 @@ package slo
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 
+	"github.com/grafana/grafana-foundation-sdk/go/cog"
 	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
 	"unobravo.com/go-obs-as-code/components"
 )
 
+// ManagedTag marks the dashboards generated from code, so the ones whose SLO was removed can be pruned
+const ManagedTag = "managed-by:go-obs-as-code"
+
 // Grafana dashboard with panels
 type Dashboard struct {
 	UID         string
 	Title       string
 	Description string
 	builder     *dashboard.DashboardBuilder
+
+	// panels counts the panels added, errs the ones that couldn't be
+	panels int
+	errs   []error
 }
 
 func NewDashboard(uid, title, description string) *Dashboard {
@@ -21,7 +31,7 @@ func NewDashboard(uid, title, description string) *Dashboard {
 		Description(description).
 		Editable().
 		Time("now-6h", "now").
-		Tags([]string{"slo"})
+		Tags([]string{"slo", ManagedTag})
 
 	return &Dashboard{
 		UID:         uid,
@@ -31,23 +41,97 @@ func NewDashboard(uid, title, description string) *Dashboard {
 	}
 }
 
-func (d *Dashboard) WithPanel(panel interface{}) *Dashboard {
-	switch p := panel.(type) {
-	case *components.StatPanel:
-		d.builder = d.builder.WithPanel(p.Build())
-	case *components.TimeSeriesPanel:
-		d.builder = d.builder.WithPanel(p.Build())
-	case *components.TextPanel:
-		d.builder = d.builder.WithPanel(p.Build())
+// WithPanel adds a panel after the previous ones.
+// A nil panel or builder is recorded as an error returned by Build and ToJSON.
+func (d *Dashboard) WithPanel(panel components.Panel) *Dashboard {
+	if builder, ok := d.panelBuilder(panel); ok {
+		d.builder = d.builder.WithPanel(builder)
 	}
 	return d
 }
 
+// panelBuilder numbers the panel and returns its builder, recording why it has none
+func (d *Dashboard) panelBuilder(panel components.Panel) (cog.Builder[dashboard.Panel], bool) {
+	d.panels++
+	if panel == nil {
+		d.errs = append(d.errs, fmt.Errorf("panel #%d: nil panel", d.panels))
+		return nil, false
+	}
+	if components.IsNil(panel) {
+		d.errs = append(d.errs, fmt.Errorf("panel #%d: nil %T", d.panels, panel))
+		return nil, false
+	}
+
+	builder := panel.PanelBuilder()
+	if components.IsNil(builder) {
+		d.errs = append(d.errs, fmt.Errorf("panel #%d: %T has no builder", d.panels, panel))
+		return nil, false
+	}
+	return builder, true
+}
+
+// WithLayout adds the panels of a layout, positioned from the top of the dashboard.
+// Panels the layout can't place are recorded as errors returned by Build and ToJSON.
+func (d *Dashboard) WithLayout(layout *components.Layout) *Dashboard {
+	rows, err := layout.Arrange()
+	if err != nil {
+		d.errs = append(d.errs, err)
+	}
+
+	for _, row := range rows {
+		if row.Title == "" {
+			for _, panel := range row.Panels() {
+				d.WithPanel(panel)
+			}
+			continue
+		}
+
+		header := row.Build()
+		if !row.Collapsed {
+			d.builder = d.builder.WithRow(header)
+			for _, panel := range row.Panels() {
+				d.WithPanel(panel)
+			}
+			continue
+		}
+
+		// Grafana only keeps the panels nested in a row while it is collapsed
+		for _, panel := range row.Panels() {
+			if builder, ok := d.panelBuilder(panel); ok {
+				header = header.WithPanel(builder)
+			}
+		}
+		d.builder = d.builder.WithRow(header)
+	}
+	return d
+}
+
+// WithDatasourceVariable adds a variable to pick any datasource of the given plugin type
+func (d *Dashboard) WithDatasourceVariable(name, pluginType, current string) *Dashboard {
+	variable := dashboard.NewDatasourceVariableBuilder(name).
+		Label("Data source").
+		Type(pluginType)
+
+	if current != "" {
+		variable = variable.Current(variableOption(current))
+	}
+
+	d.builder = d.builder.WithVariable(variable)
+	return d
+}
+
 func (d *Dashboard) Build() (*dashboard.DashboardBuilder, error) {
+	if err := errors.Join(d.errs...); err != nil {
+		return nil, err
+	}
 	return d.builder, nil
 }
 
 func (d *Dashboard) ToJSON() (string, error) {
+	if err := errors.Join(d.errs...); err != nil {
+		return "", err
+	}
+
 	dashboard, err := d.builder.Build()
 	if err != nil {
 		return "", err
@@ -0,0 +1,83 @@
+package slo_test
+
+import (
+	"testing"
+	"time"
+
+	"unobravo.com/go-obs-as-code/internal/golden"
+	"unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+var (
+	serverErrors = `http_requests_total{job="api", code=~"5.."}`
+	requests     = `http_requests_total{job="api"}`
+	latency      = promql.NewHistogram("http_request_duration_seconds", promql.Equal("job", "api"))
+
+	createdAt = time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)
+)
+
+// TestDashboardGolden builds every kind of SLO dashboard from fixed inputs.
+// Run with -update to regenerate testdata after an intended change.
+func TestDashboardGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		slo  slo.SLO
+	}{
+		{
+			name: "availability",
+			slo: slo.NewAvailabilitySLO("api-availability", "API Availability", "Share of API requests served without a server error",
+				slo.Week*4, 0.999, serverErrors, requests).
+				WithLabels(map[string]string{"service": "api"}).
+				WithCreatedAt(createdAt),
+		},
+		{
+			name: "availability_recording_rules",
+			slo: slo.NewAvailabilitySLO("api-availability", "API Availability", "Share of API requests served without a server error",
+				slo.Week*4, 0.999, serverErrors, requests).
+				WithRecordingRules(),
+		},
+		{
+			name: "latency",
+			slo: slo.NewLatencySLO("api-latency", "API Latency", "Share of API requests served under 250ms",
+				slo.Week*4, 0.95, latency.Bucket("0.25").String(), latency.Count().String()),
+		},
+		{
+			name: "latency_threshold",
+			slo: slo.NewThresholdLatencySLO("api-latency-threshold", "API", slo.Day*30, 0.99,
+				latency, slo.Seconds, 400*time.Millisecond),
+		},
+		{
+			name: "latency_burn_rate_policy",
+			slo: slo.NewThresholdLatencySLO("api-latency-weekly", "API", slo.Week, 0.95,
+				latency, slo.Seconds, time.Second).
+				WithBurnRatePolicy(slo.BurnRatePolicy{{
+					Name:     "SLOBurn",
+					Title:    "Burn Rate Alert",
+					Severity: "page",
+					For:      5 * time.Minute,
+					Windows:  []slo.BurnRateWindow{{Short: 10 * slo.Minute, Long: slo.Hour, BudgetConsumed: 0.05}},
+				}}),
+		},
+		{
+			name: "availability_templated",
+			slo: slo.NewAvailabilitySLO("api-availability-templated", "API Availability", "Share of API requests served without a server error",
+				slo.Week*4, 0.999, serverErrors, requests).
+				WithDatasource(slo.Datasource{Type: "prometheus", UID: "prometheus", Variable: true}).
+				WithTemplating(
+					slo.NewBadEventsSLI(`http_requests_total{job=~"$job", code=~"5.."}`, `http_requests_total{job=~"$job"}`),
+					slo.NewCustomVariable("job", []string{"api", "worker"}, "api"),
+				),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.slo.BuildJSON()
+			if err != nil {
+				t.Fatalf("BuildJSON: %v", err)
+			}
+			golden.Assert(t, tt.name+".json", got+"\n")
+		})
+	}
+}
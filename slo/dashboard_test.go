@@ -0,0 +1,68 @@
+package slo_test
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+	"unobravo.com/go-obs-as-code/components"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+type layoutPanel struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	GridPos dashboard.GridPos `json:"gridPos"`
+	Panels  []layoutPanel     `json:"panels"`
+}
+
+func titles(panels []layoutPanel) []string {
+	var names []string
+	for _, p := range panels {
+		names = append(names, p.Type+" "+p.Title)
+	}
+	return names
+}
+
+func TestWithLayoutNestsCollapsedRows(t *testing.T) {
+	text := func(title string) *components.TextPanel {
+		return components.NewTextPanel(title, "", dashboard.GridPos{})
+	}
+
+	layout := components.NewLayout()
+	layout.Row("").WithPanel(text("Summary"), components.Full)
+	layout.Row("Open").WithPanel(text("Visible"), components.Half).WithPanel(text("Also visible"), components.Half)
+	layout.Row("Details").Collapse().WithPanel(text("Nested"), components.Full).WithPanel(text("Also nested"), components.Full)
+	layout.Row("Last").WithPanel(text("Bottom"), components.Full)
+
+	out, err := slo.NewDashboard("layout", "Layout", "").WithLayout(layout).ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model struct {
+		Panels []layoutPanel `json:"panels"`
+	}
+	if err := json.Unmarshal([]byte(out), &model); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"text Summary", "row Open", "text Visible", "text Also visible", "row Details", "row Last", "text Bottom"}
+	if got := titles(model.Panels); !slices.Equal(got, want) {
+		t.Fatalf("top-level panels %q, want %q", got, want)
+	}
+
+	details := model.Panels[4]
+	if got, want := titles(details.Panels), []string{"text Nested", "text Also nested"}; !slices.Equal(got, want) {
+		t.Errorf("collapsed row holds %q, want %q", got, want)
+	}
+	if got := details.Panels[0].GridPos.Y; got != details.GridPos.Y+1 {
+		t.Errorf("nested panel at y=%d, want right under its header at y=%d", got, details.GridPos.Y+1)
+	}
+	if last := model.Panels[5]; last.GridPos.Y != details.GridPos.Y+1 {
+		t.Errorf("row after the collapsed one at y=%d, want %d", last.GridPos.Y, details.GridPos.Y+1)
+	}
+	if open := model.Panels[1]; len(open.Panels) != 0 {
+		t.Errorf("expanded row holds %q, want its panels at the top level", titles(open.Panels))
+	}
+}
@@ -0,0 +1,23 @@
+package slo
+
+// Datasource is the Prometheus-compatible datasource an SLO's panels query
+type Datasource struct {
+	Type string
+	UID  string
+
+	// Variable adds a ${datasource} dashboard variable, preselecting UID, that every panel queries
+	Variable bool
+}
+
+// DefaultDatasource is the Grafana Cloud Prometheus the dashboards were first written for
+var DefaultDatasource = Datasource{Type: "prometheus", UID: "grafanacloud-prom"}
+
+const datasourceVariable = "datasource"
+
+// panelUID is the datasource UID referenced by the panels
+func (ds Datasource) panelUID() string {
+	if ds.Variable {
+		return "${" + datasourceVariable + "}"
+	}
+	return ds.UID
+}
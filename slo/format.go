@@ -0,0 +1,17 @@
+package slo
+
+import (
+	"math"
+	"strconv"
+)
+
+// FormatPercent renders a ratio such as 0.999 as "99.9%"
+func FormatPercent(ratio float64) string {
+	percent := math.Round(ratio*100*1e6) / 1e6
+	return strconv.FormatFloat(percent, 'f', -1, 64) + "%"
+}
+
+// FormatFactor renders a burn-rate factor such as 14.4 without trailing zeros
+func FormatFactor(factor float64) string {
+	return strconv.FormatFloat(factor, 'g', -1, 64)
+}
@@ -5,21 +5,27 @@ import (
 
 	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
 	"unobravo.com/go-obs-as-code/components"
+	"unobravo.com/go-obs-as-code/promql"
 )
 
 type LatencySLO struct {
 	UID                string
 	Name               string
 	Description        string
-	TimeWindow         string
+	TimeWindow         Window
 	Target             float64
 	SuccessMetricQuery string
 	TotalMetricQuery   string
+	Labels             map[string]string
+	Datasource         Datasource
+	BurnRatePolicy     BurnRatePolicy
+	Variables          []Variable
 	dashboard          *Dashboard
-	queries            *LatencyQueries
+	queries            *SLIQueries
+	templated          *SLIQueries
 }
 
-func NewLatencySLO(uid, name, description, timeWindow string, target float64, successMetricQuery, totalMetricQuery string) *LatencySLO {
+func NewLatencySLO(uid, name, description string, timeWindow Window, target float64, successMetricQuery, totalMetricQuery string) *LatencySLO {
 	slo := &LatencySLO{
 		UID:                uid,
 		Name:               name,
@@ -28,102 +34,128 @@ func NewLatencySLO(uid, name, description, timeWindow string, target float64, su
 		Target:             target,
 		SuccessMetricQuery: successMetricQuery,
 		TotalMetricQuery:   totalMetricQuery,
-		dashboard:          NewDashboard(uid, name, description),
-		queries:            NewLatencyQueries(successMetricQuery, totalMetricQuery, target, timeWindow),
+		Datasource:         DefaultDatasource,
+		BurnRatePolicy:     DefaultBurnRatePolicy,
+		queries:            NewSLIQueries(NewGoodEventsSLI(successMetricQuery, totalMetricQuery), target, timeWindow),
 	}
 
-	slo.buildRecapRow()
-	slo.buildSliRow()
-	slo.buildErrorBudgetRow()
-	slo.buildBurnRateRow()
-	slo.buildEventRateRow()
+	return slo
+}
+
+// NewHistogramLatencySLO counts the requests in the le bucket of a histogram as good events
+func NewHistogramLatencySLO(uid, name, description string, timeWindow Window, target float64, histogram promql.Histogram, le string) *LatencySLO {
+	return NewLatencySLO(uid, name, description, timeWindow, target, histogram.Bucket(le).String(), histogram.Count().String())
+}
+
+// WithLabels attaches labels that identify the SLO in generated alerts
+func (slo *LatencySLO) WithLabels(labels map[string]string) *LatencySLO {
+	slo.Labels = labels
+	return slo
+}
+
+func (slo *LatencySLO) Info() Info {
+	return Info{
+		UID:         slo.UID,
+		Name:        slo.Name,
+		Description: slo.Description,
+		Kind:        "latency",
+		TimeWindow:  slo.TimeWindow,
+		Target:      slo.Target,
+		SLI:         slo.queries.SLI,
+		Labels:      slo.Labels,
+		Datasource:  slo.Datasource,
+		BurnRate:    slo.BurnRatePolicy,
+	}
+}
 
+// WithBurnRatePolicy replaces the default burn-rate alerts shown on the dashboard
+func (slo *LatencySLO) WithBurnRatePolicy(policy BurnRatePolicy) *LatencySLO {
+	slo.BurnRatePolicy = policy
 	return slo
 }
 
+// WithDatasource sets the datasource the dashboard panels query
+func (slo *LatencySLO) WithDatasource(ds Datasource) *LatencySLO {
+	slo.Datasource = ds
+	return slo
+}
+
+// WithRecordingRules makes the dashboard and alerts read the SLO's recording rules
+// instead of evaluating the raw selectors
+func (slo *LatencySLO) WithRecordingRules() *LatencySLO {
+	slo.queries.UseRecordingRules(slo.UID)
+	return slo
+}
+
+func (slo *LatencySLO) Queries() Queries {
+	return slo.queries
+}
+
+// WithTemplating makes the dashboard query sli, whose selectors reference the variables,
+// while alerts and recording rules keep the SLO's own selectors
+func (slo *LatencySLO) WithTemplating(sli SLI, variables ...Variable) *LatencySLO {
+	slo.Variables = variables
+	slo.templated = NewSLIQueries(sli, slo.Target, slo.TimeWindow)
+	return slo
+}
+
+// panelQueries are the queries of the dashboard panels
+func (slo *LatencySLO) panelQueries() *SLIQueries {
+	if slo.templated != nil {
+		return slo.templated
+	}
+	return slo.queries
+}
+
 func (slo *LatencySLO) BuildJSON() (string, error) {
+	slo.dashboard = NewDashboard(slo.UID, slo.Name, slo.Description)
+	if slo.Datasource.Variable {
+		slo.dashboard.WithDatasourceVariable(datasourceVariable, slo.Datasource.Type, slo.Datasource.UID)
+	}
+	for _, variable := range slo.Variables {
+		slo.dashboard.WithVariable(variable)
+	}
+
+	layout := components.NewLayout()
+	slo.buildRecapRow(layout.Row("").WithHeight(4))
+	slo.buildSliRow(layout.Row("").WithHeight(7))
+	slo.buildErrorBudgetRow(layout.Row("").WithHeight(7))
+	slo.buildBurnRateRow(layout.Row("").WithHeight(7))
+	slo.buildEventRateRow(layout.Row("").WithHeight(7))
+	slo.dashboard.WithLayout(layout)
+
 	return slo.dashboard.ToJSON()
 }
 
 // buildRecapRow builds the first row with recap information
-func (slo *LatencySLO) buildRecapRow() {
+func (slo *LatencySLO) buildRecapRow(row *components.Row) {
 	// Text panel with title
-	textPanel := components.NewTextPanel("", "# "+slo.Name, dashboard.GridPos{H: 4, W: 7, X: 0, Y: 0})
-	slo.dashboard.WithPanel(textPanel)
+	textPanel := components.NewTextPanel("", "# "+slo.Name, dashboard.GridPos{})
+	row.WithPanel(textPanel, components.Columns(7))
 
-	// Fast burn rate alert panel
+	// Burn rate alert panels, sharing the 8 columns after the title
 	prometheusDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("short"),
 		Decimals: float64Ptr(0),
 	}
-
-	fastBurnTarget := components.NewPrometheusQuery("fast_burn_alert", slo.queries.FastBurnRateQuery())
-	fastBurnPanel := components.NewStatPanel(
-		"🚨 Fast Burn Rate Alert",
-		"Critical alert when burn rate exceeds fast burn thresholds:\n• 14.4x for 5min AND 1hour\n• 6x for 30min AND 6hour",
-		dashboard.GridPos{H: 4, W: 4, X: 7, Y: 0},
-	).WithDatasource(prometheusDS).
-		WithTarget(fastBurnTarget).
-		WithMappings([]dashboard.ValueMapping{
-			{
-				ValueMap: &dashboard.ValueMap{
-					Type: dashboard.MappingTypeValueToText,
-					Options: map[string]dashboard.ValueMappingResult{
-						"0": {
-							Text:  stringPtr("OK"),
-							Color: stringPtr("green"),
-						},
-						"1": {
-							Text:  stringPtr("FIRING"),
-							Color: stringPtr("red"),
-						},
-					},
-				},
-			}})
-	slo.dashboard.WithPanel(fastBurnPanel)
-
-	// Slow burn rate alert panel
-	slowBurnTarget := components.NewPrometheusQuery("slow_burn_alert", slo.queries.SlowBurnRateQuery())
-	slowBurnPanel := components.NewStatPanel(
-		"⚠️ Slow Burn Rate Alert",
-		"Warning alert for slow burn rate:\n• 3x for 2hours AND 24hours\n• 1x for 6hours AND 72hours",
-		dashboard.GridPos{H: 4, W: 4, X: 11, Y: 0},
-	).WithDatasource(prometheusDS).
-		WithTarget(slowBurnTarget).
-		WithMappings([]dashboard.ValueMapping{
-			{
-				ValueMap: &dashboard.ValueMap{
-					Type: dashboard.MappingTypeValueToText,
-					Options: map[string]dashboard.ValueMappingResult{
-						"0": {
-							Text:  stringPtr("OK"),
-							Color: stringPtr("green"),
-						},
-						"1": {
-							Text:  stringPtr("CRITICAL"),
-							Color: stringPtr("red"),
-						},
-					},
-				},
-			}})
-	slo.dashboard.WithPanel(slowBurnPanel)
+	burnRateAlertPanels(row, slo.BurnRatePolicy, slo.panelQueries(), slo.TimeWindow, prometheusDS, 8)
 
 	// Time window panel
 	timeWindowDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 		Min:  float64Ptr(0),
 		Max:  float64Ptr(1),
 	}
 
-	timeWindowTarget := components.NewPrometheusQuery("time_window", slo.queries.TimeWindowQuery())
+	timeWindowTarget := components.NewPrometheusQuery("time_window", slo.panelQueries().TimeWindowQuery())
 	timeWindowPanel := components.NewStatPanel(
 		"Time Window",
 		"The time window over which the service level objective is being measured over",
-		dashboard.GridPos{H: 4, W: 4, X: 15, Y: 0},
+		dashboard.GridPos{},
 	).WithDatasource(timeWindowDS).
 		WithTarget(timeWindowTarget).
 		WithOptions(&components.StatPanelOptions{
@@ -161,41 +193,41 @@ func (slo *LatencySLO) buildRecapRow() {
 				},
 			},
 		})
-	slo.dashboard.WithPanel(timeWindowPanel)
+	row.WithPanel(timeWindowPanel, components.Columns(4))
 
 	// SLO target panel
 	sloTargetDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("percentunit"),
 		Decimals: float64Ptr(2),
 		Min:      float64Ptr(0),
 		Max:      float64Ptr(1),
 	}
 
-	sloTarget := components.NewPrometheusQuery("A", slo.queries.SLOTargetQuery())
+	sloTarget := components.NewPrometheusQuery("A", slo.panelQueries().SLOTargetQuery())
 	sloPanel := components.NewStatPanel(
 		"SLO",
 		"The SLO's Objective value. Always between 0 and 100%",
-		dashboard.GridPos{H: 4, W: 5, X: 19, Y: 0},
+		dashboard.GridPos{},
 	).WithDatasource(sloTargetDS).WithTarget(sloTarget)
-	slo.dashboard.WithPanel(sloPanel)
+	row.WithPanel(sloPanel, components.Columns(5))
 }
 
 // SLI row
-func (slo *LatencySLO) buildSliRow() {
+func (slo *LatencySLO) buildSliRow(row *components.Row) {
 	// SLI timeseries panel
 	sliDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 	}
 
-	sliTarget := components.NewPrometheusQuery("custom_sli", slo.queries.SLIQuery()).WithLegend("SLI")
+	sliTarget := components.NewPrometheusQuery("custom_sli", slo.panelQueries().SLIQuery()).WithLegend("SLI")
 	sliPanel := components.NewTimeSeriesPanel(
 		"SLI",
 		"Service level indicator",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 4},
+		dashboard.GridPos{},
 	).WithDatasource(sliDS).WithTarget(sliTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "red",
@@ -206,24 +238,24 @@ func (slo *LatencySLO) buildSliRow() {
 			Value: float64Ptr(slo.Target),
 		},
 	})
-	slo.dashboard.WithPanel(sliPanel)
+	row.WithPanel(sliPanel, components.Columns(19))
 
-	// SLI 28d stat panel
-	sli28dDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+	// SLI stat panel over the whole window
+	sliWindowDS := &components.DatasourceConfig{
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("percentunit"),
 		Decimals: float64Ptr(1),
 		Min:      float64Ptr(0),
 		Max:      float64Ptr(1),
 	}
 
-	sli28dTarget := components.NewPrometheusQuery("custom_sli_28d", slo.queries.SLITimeWindowQuery()).WithInterval("1m")
-	sli28dPanel := components.NewStatPanel(
-		"SLI (last 28d)",
-		"Service level indicator's value over the last 28d",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 4},
-	).WithDatasource(sli28dDS).WithTarget(sli28dTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
+	sliWindowTarget := components.NewPrometheusQuery("custom_sli_window", slo.panelQueries().SLITimeWindowQuery()).WithInterval("1m")
+	sliWindowPanel := components.NewStatPanel(
+		fmt.Sprintf("SLI (last %s)", slo.TimeWindow),
+		fmt.Sprintf("Service level indicator's value over the last %s", slo.TimeWindow.Describe()),
+		dashboard.GridPos{},
+	).WithDatasource(sliWindowDS).WithTarget(sliWindowTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "red",
 			Value: float64Ptr(0),
@@ -233,28 +265,28 @@ func (slo *LatencySLO) buildSliRow() {
 			Value: float64Ptr(slo.Target),
 		},
 	})
-	slo.dashboard.WithPanel(sli28dPanel)
+	row.WithPanel(sliWindowPanel, components.Columns(5))
 }
 
 // buildErrorBudgetRow builds the error budget row
-func (slo *LatencySLO) buildErrorBudgetRow() {
+func (slo *LatencySLO) buildErrorBudgetRow(row *components.Row) {
 	// Error budget trend timeseries
 	budgetDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("percentunit"),
 	}
 
 	// Primary query for failure events in range
-	failureEventsTarget := components.NewPrometheusQuery("Failure in Range", slo.queries.BurndownFailureEventsQuery()).WithLegend("failureEventsInRange")
+	failureEventsTarget := components.NewPrometheusQuery("Failure in Range", slo.panelQueries().BurndownFailureEventsQuery()).WithLegend("failureEventsInRange")
 
 	// Secondary query for total events
-	totalEventsTarget := components.NewPrometheusQuery("Total Events", slo.queries.BurndownTotalEventsQuery()).WithLegend("totalEvents")
+	totalEventsTarget := components.NewPrometheusQuery("Total Events", slo.panelQueries().BurndownTotalEventsQuery()).WithLegend("totalEvents")
 
 	budgetTrendPanel := components.NewTimeSeriesPanel(
 		"Error Budget Burndown",
 		"The error budget burndown in the selected time\nThe error budget burndown in the selected time",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 11},
+		dashboard.GridPos{},
 	).WithDatasource(budgetDS).
 		WithTarget(failureEventsTarget).
 		WithTarget(totalEventsTarget).
@@ -406,23 +438,23 @@ func (slo *LatencySLO) buildErrorBudgetRow() {
 			Value: float64Ptr(0.2),
 		},
 	})
-	slo.dashboard.WithPanel(budgetTrendPanel)
+	row.WithPanel(budgetTrendPanel, components.Columns(19))
 
 	// Remaining error budget
 	remainingBudgetDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("percentunit"),
 		Min:      float64Ptr(0),
 		Max:      float64Ptr(1),
 		Decimals: float64Ptr(1),
 	}
 
-	remainingBudgetTarget := components.NewPrometheusQuery("custom_remaining_error_budget", slo.queries.RemainingErrorBudgetQuery())
+	remainingBudgetTarget := components.NewPrometheusQuery("custom_remaining_error_budget", slo.panelQueries().RemainingErrorBudgetQuery())
 	remainingBudgetPanel := components.NewStatPanel(
 		"Remaining Error Budget",
-		"The unspent error budget over the last 28d window",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 11},
+		fmt.Sprintf("The unspent error budget over the last %s window", slo.TimeWindow),
+		dashboard.GridPos{},
 	).WithDatasource(remainingBudgetDS).WithTarget(remainingBudgetTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "red",
@@ -437,23 +469,23 @@ func (slo *LatencySLO) buildErrorBudgetRow() {
 			Value: float64Ptr(0.2),
 		},
 	})
-	slo.dashboard.WithPanel(remainingBudgetPanel)
+	row.WithPanel(remainingBudgetPanel, components.Columns(5))
 }
 
 // The burn rate row
-func (slo *LatencySLO) buildBurnRateRow() {
+func (slo *LatencySLO) buildBurnRateRow(row *components.Row) {
 	// Burn rate timeseries
 	burnRateDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("none"),
 	}
 
-	burnRateTarget := components.NewPrometheusQuery("custom_burn_rate", slo.queries.BurnRateQuery()).WithLegend("Burn Rate")
+	burnRateTarget := components.NewPrometheusQuery("custom_burn_rate", slo.panelQueries().BurnRateQuery()).WithLegend("Burn Rate")
 	burnRatePanel := components.NewTimeSeriesPanel(
 		"Error Budget Burn Rate",
 		"The burn rate is the rate that this SLO is spending its error budget over last 5 min [0, 1.0]. A 1x burn rate will consume the entire error budget allotted for that period.",
-		dashboard.GridPos{H: 7, W: 19, X: 0, Y: 18},
+		dashboard.GridPos{},
 	).WithDatasource(burnRateDS).WithTarget(burnRateTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "green",
@@ -468,21 +500,21 @@ func (slo *LatencySLO) buildBurnRateRow() {
 			Value: float64Ptr(3),
 		},
 	})
-	slo.dashboard.WithPanel(burnRatePanel)
+	row.WithPanel(burnRatePanel, components.Columns(19))
 
 	// Current burn rate stat
 	currentBurnDS := &components.DatasourceConfig{
-		Type:     "prometheus",
-		UID:      "grafanacloud-prom",
+		Type:     slo.Datasource.Type,
+		UID:      slo.Datasource.panelUID(),
 		Unit:     stringPtr("none"),
 		Decimals: float64Ptr(2),
 	}
 
-	currentBurnTarget := components.NewPrometheusQuery("current_burn_rate", slo.queries.InstantBurnRateQuery())
+	currentBurnTarget := components.NewPrometheusQuery("current_burn_rate", slo.panelQueries().InstantBurnRateQuery())
 	currentBurnPanel := components.NewStatPanel(
 		"Current Burn Rate",
 		"The burn rate is the rate that this SLO is spending its error budget over last 5 min [0, 1.0]. A 1x burn rate will consume the entire error budget allotted for that period.",
-		dashboard.GridPos{H: 7, W: 5, X: 19, Y: 18},
+		dashboard.GridPos{},
 	).WithDatasource(currentBurnDS).WithTarget(currentBurnTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "green",
@@ -497,23 +529,23 @@ func (slo *LatencySLO) buildBurnRateRow() {
 			Value: float64Ptr(3),
 		},
 	})
-	slo.dashboard.WithPanel(currentBurnPanel)
+	row.WithPanel(currentBurnPanel, components.Columns(5))
 }
 
 // The event rate row
-func (slo *LatencySLO) buildEventRateRow() {
+func (slo *LatencySLO) buildEventRateRow(row *components.Row) {
 	// Event rate timeseries
 	eventRateDS := &components.DatasourceConfig{
-		Type: "prometheus",
-		UID:  "grafanacloud-prom",
+		Type: slo.Datasource.Type,
+		UID:  slo.Datasource.panelUID(),
 		Unit: stringPtr("reqps"),
 	}
 
-	eventRateTarget := components.NewPrometheusQuery("event_rate", slo.queries.EventRateQuery()).WithLegend("Event Rate")
+	eventRateTarget := components.NewPrometheusQuery("event_rate", slo.panelQueries().EventRateQuery()).WithLegend("Event Rate")
 	eventRatePanel := components.NewTimeSeriesPanel(
 		"Event Rate",
 		"Total Rate (for SLIs that compare rate of successful events to rate of total events, this is the latter)",
-		dashboard.GridPos{H: 7, W: 24, X: 0, Y: 25},
+		dashboard.GridPos{},
 	).WithDatasource(eventRateDS).WithTarget(eventRateTarget).WithThresholds(dashboard.ThresholdsModeAbsolute, []dashboard.Threshold{
 		{
 			Color: "red",
@@ -524,5 +556,5 @@ func (slo *LatencySLO) buildEventRateRow() {
 			Value: float64Ptr(0),
 		},
 	})
-	slo.dashboard.WithPanel(eventRatePanel)
+	row.WithPanel(eventRatePanel, components.Full)
 }
@@ -0,0 +1,38 @@
+package slo
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"unobravo.com/go-obs-as-code/promql"
+)
+
+// HistogramUnit is the unit the le buckets of a latency histogram are expressed in
+type HistogramUnit string
+
+const (
+	Seconds      HistogramUnit = "seconds"
+	Milliseconds HistogramUnit = "milliseconds"
+)
+
+// Bucket returns the le label value of the bucket counting requests faster than threshold
+func (u HistogramUnit) Bucket(threshold time.Duration) string {
+	value := threshold.Seconds()
+	if u == Milliseconds {
+		value = float64(threshold) / float64(time.Millisecond)
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// NewThresholdLatencySLO counts the requests faster than threshold as good events.
+// The le bucket, the dashboard title and its description are all derived from threshold,
+// so they can't drift apart; subject names what is measured, e.g. "Send Message".
+func NewThresholdLatencySLO(uid, subject string, timeWindow Window, target float64, histogram promql.Histogram, unit HistogramUnit, threshold time.Duration) *LatencySLO {
+	name := fmt.Sprintf("%s Latency SLO - %s requests < %s over %s",
+		subject, FormatPercent(target), threshold, timeWindow.Describe())
+	description := fmt.Sprintf("Dashboard to track the latency of the %s service: %s of requests should have latency < %s over %s",
+		subject, FormatPercent(target), threshold, timeWindow.Describe())
+
+	return NewHistogramLatencySLO(uid, name, description, timeWindow, target, histogram, unit.Bucket(threshold))
+}
@@ -0,0 +1,131 @@
+package slo
+
+import "fmt"
+
+// SLIQueries derives every dashboard and rule expression of an SLO from its SLI
+type SLIQueries struct {
+	SLI        SLI
+	Target     float64
+	TimeWindow Window
+
+	// RecordedSLO is the UID whose recording rules are read instead of the raw selectors
+	RecordedSLO string
+}
+
+func NewSLIQueries(sli SLI, target float64, timeWindow Window) *SLIQueries {
+	return &SLIQueries{
+		SLI:        sli,
+		Target:     target,
+		TimeWindow: timeWindow,
+	}
+}
+
+// UseRecordingRules reads the recording rules of the given SLO wherever one exists
+func (q *SLIQueries) UseRecordingRules(sloUID string) *SLIQueries {
+	q.RecordedSLO = sloUID
+	return q
+}
+
+// SLIQuery is the share of good events at each point of the dashboard's time range
+func (q *SLIQueries) SLIQuery() string {
+	return fmt.Sprintf(`1 - (%s)`, q.ErrorRatioQuery("$__rate_interval"))
+}
+
+// SLITimeWindowQuery is the share of good events over the whole SLO window
+func (q *SLIQueries) SLITimeWindowQuery() string {
+	return fmt.Sprintf(`1 - %s`, q.windowErrorRatio(q.TimeWindow.Step()))
+}
+
+// BurnRateConditionQuery holds while the alert fires, as evaluated by the Prometheus alerting rule
+func (q *SLIQueries) BurnRateConditionQuery(alert BurnRateAlert) string {
+	return BurnRateCondition(q.WindowBurnRateQuery, alert, q.TimeWindow)
+}
+
+// BurnRateAlertQuery is 1 while the alert fires and 0 otherwise
+func (q *SLIQueries) BurnRateAlertQuery(alert BurnRateAlert) string {
+	return burnRateAlertQuery(q.WindowBurnRateQuery, alert, q.TimeWindow)
+}
+
+// WindowBurnRateQuery is the rate the error budget is being spent at, averaged over window
+func (q *SLIQueries) WindowBurnRateQuery(window string) string {
+	return fmt.Sprintf(`%s / (1 - %f)`, q.errorRatio(window), q.Target)
+}
+
+// ErrorRatioQuery is the share of bad events over window, always computed from the raw selectors
+func (q *SLIQueries) ErrorRatioQuery(window string) string {
+	return fmt.Sprintf(`(%s) / %s`, q.SLI.BadEvents(window), q.SLI.TotalEvents(window))
+}
+
+// ErrorRateQuery is the per-second rate of bad events, always computed from the raw selectors
+func (q *SLIQueries) ErrorRateQuery() string {
+	return q.SLI.BadEvents("5m")
+}
+
+// TotalRateQuery is the per-second rate of events, always computed from the raw selectors
+func (q *SLIQueries) TotalRateQuery() string {
+	return q.SLI.TotalEvents("5m")
+}
+
+func (q *SLIQueries) TimeWindowQuery() string {
+	return fmt.Sprintf(`label_replace(vector(1), "time_period", "%s", "", "")`, q.TimeWindow)
+}
+
+func (q *SLIQueries) SLOTargetQuery() string {
+	return fmt.Sprintf("vector(%f)", q.Target)
+}
+
+// ErrorBudgetTrendQuery is the share of the error budget left, coarse enough to chart over time
+func (q *SLIQueries) ErrorBudgetTrendQuery() string {
+	return q.remainingErrorBudget(q.TimeWindow.TrendStep())
+}
+
+// RemainingErrorBudgetQuery is the share of the error budget left over the SLO window
+func (q *SLIQueries) RemainingErrorBudgetQuery() string {
+	return q.remainingErrorBudget(q.TimeWindow.Step())
+}
+
+// BurnRateQuery is the 5m burn rate averaged over each point of the dashboard's time range
+func (q *SLIQueries) BurnRateQuery() string {
+	return fmt.Sprintf(`avg_over_time(%s[$__interval:]) / (1 - %f)`, q.errorRatio("5m"), q.Target)
+}
+
+func (q *SLIQueries) InstantBurnRateQuery() string {
+	return q.WindowBurnRateQuery("5m")
+}
+
+func (q *SLIQueries) EventRateQuery() string {
+	return q.SLI.TotalEvents("$__rate_interval")
+}
+
+// BurndownFailureEventsQuery counts the bad events in each interval, from 5m samples
+func (q *SLIQueries) BurndownFailureEventsQuery() string {
+	return fmt.Sprintf(`300 * sum_over_time((%s)[$__interval:5m] offset 1s)`, q.SLI.BadEvents("5m"))
+}
+
+// BurndownTotalEventsQuery counts all the events in the dashboard's time range, from 5m samples
+func (q *SLIQueries) BurndownTotalEventsQuery() string {
+	return fmt.Sprintf(`300 * sum_over_time((%s)[$__range:5m] @ ${__to:date:seconds} offset 1s)`, q.SLI.TotalEvents("5m"))
+}
+
+// errorRatio reads the recorded error ratio over window, if there is one
+func (q *SLIQueries) errorRatio(window string) string {
+	if ratio, ok := recordedErrorRatio(q.RecordedSLO, window, q.TimeWindow); ok {
+		return ratio
+	}
+	return "(" + q.ErrorRatioQuery(window) + ")"
+}
+
+// windowErrorRatio is the error ratio over the SLO window, summing rates sampled every step
+// over ranges as long as the step, so no event between two samples is left out
+func (q *SLIQueries) windowErrorRatio(step Window) string {
+	if ratio, ok := recordedErrorRatio(q.RecordedSLO, q.TimeWindow.String(), q.TimeWindow); ok {
+		return ratio
+	}
+	rateRange := step.rateRange().String()
+	return fmt.Sprintf(`(sum_over_time((%s)[%s:%s]) / sum_over_time((%s)[%s:%s]))`,
+		q.SLI.BadEvents(rateRange), q.TimeWindow, step, q.SLI.TotalEvents(rateRange), q.TimeWindow, step)
+}
+
+func (q *SLIQueries) remainingErrorBudget(step Window) string {
+	return fmt.Sprintf(`((1 - %s) - %f) / (1 - %f)`, q.windowErrorRatio(step), q.Target, q.Target)
+}
@@ -0,0 +1,264 @@
+package slo_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"unobravo.com/go-obs-as-code/internal/promtest"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+var (
+	fastBurn = slo.DefaultBurnRatePolicy[0]
+	slowBurn = slo.DefaultBurnRatePolicy[1]
+)
+
+func newAvailability() slo.SLO {
+	return slo.NewAvailabilitySLO("api-availability", "API Availability", "Share of API requests served without a server error",
+		slo.Week*4, 0.999, serverErrors, requests)
+}
+
+func newLatency() slo.SLO {
+	return slo.NewLatencySLO("api-latency", "API Latency", "Share of API requests served under 250ms",
+		slo.Week*4, 0.999, latency.Bucket("0.25").String(), latency.Count().String())
+}
+
+// TestQueriesEvaluate runs every query of each kind of SLO against matching series
+func TestQueriesEvaluate(t *testing.T) {
+	tests := []struct {
+		name   string
+		slo    slo.SLO
+		series string
+	}{
+		{
+			name: "availability",
+			slo:  newAvailability(),
+			series: `load 1m
+				http_requests_total{job="api", code="200"} 0+98x360
+				http_requests_total{job="api", code="500"} 0+2x360`,
+		},
+		{
+			name: "latency",
+			slo:  newLatency(),
+			series: `load 1m
+				http_request_duration_seconds_bucket{job="api", le="0.25"} 0+98x360
+				http_request_duration_seconds_count{job="api"} 0+100x360`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := promtest.Load(t, tt.series)
+			// A 5m resolution puts one 5m sample in each interval of the burndown
+			s.Grafana.Interval = 5 * time.Minute
+			// The series span a 4h boundary, where the trend query takes its first point
+			at := 6 * time.Hour
+			q := tt.slo.Queries()
+
+			exprs := map[string]string{
+				"SLIQuery":                   q.SLIQuery(),
+				"SLITimeWindowQuery":         q.SLITimeWindowQuery(),
+				"WindowBurnRateQuery":        q.WindowBurnRateQuery("1h"),
+				"ErrorRatioQuery":            q.ErrorRatioQuery("1h"),
+				"ErrorRateQuery":             q.ErrorRateQuery(),
+				"TotalRateQuery":             q.TotalRateQuery(),
+				"TimeWindowQuery":            q.TimeWindowQuery(),
+				"SLOTargetQuery":             q.SLOTargetQuery(),
+				"ErrorBudgetTrendQuery":      q.ErrorBudgetTrendQuery(),
+				"RemainingErrorBudgetQuery":  q.RemainingErrorBudgetQuery(),
+				"BurnRateQuery":              q.BurnRateQuery(),
+				"InstantBurnRateQuery":       q.InstantBurnRateQuery(),
+				"EventRateQuery":             q.EventRateQuery(),
+				"BurndownFailureEventsQuery": q.BurndownFailureEventsQuery(),
+				"BurndownTotalEventsQuery":   q.BurndownTotalEventsQuery(),
+			}
+
+			// 2% of the requests are bad throughout
+			want := map[string]float64{
+				"SLIQuery":                   0.98,
+				"SLITimeWindowQuery":         0.98,
+				"WindowBurnRateQuery":        20,
+				"ErrorRatioQuery":            0.02,
+				"ErrorRateQuery":             2.0 / 60,
+				"TotalRateQuery":             100.0 / 60,
+				"TimeWindowQuery":            1,
+				"SLOTargetQuery":             0.999,
+				"ErrorBudgetTrendQuery":      -19,
+				"RemainingErrorBudgetQuery":  -19,
+				"BurnRateQuery":              20,
+				"InstantBurnRateQuery":       20,
+				"EventRateQuery":             100.0 / 60,
+				"BurndownFailureEventsQuery": 2 * 5,
+				"BurndownTotalEventsQuery":   100 * 60,
+			}
+
+			// A 20x burn rate exceeds every factor of the default policy: the condition holds
+			// with the burn rate over its first long window, and the alert query is 1
+			for _, alert := range slo.DefaultBurnRatePolicy {
+				exprs["BurnRateConditionQuery "+alert.Name] = q.BurnRateConditionQuery(alert)
+				want["BurnRateConditionQuery "+alert.Name] = 20
+				exprs["BurnRateAlertQuery "+alert.Name] = q.BurnRateAlertQuery(alert)
+				want["BurnRateAlertQuery "+alert.Name] = 1
+			}
+
+			for name, expr := range exprs {
+				w, ok := want[name]
+				if !ok {
+					t.Errorf("%s has no expected value", name)
+					continue
+				}
+				if got := s.Value(expr, at); !approx(got, w) {
+					t.Errorf("%s = %g, want %g\n%s", name, got, w, expr)
+				}
+			}
+			for name := range want {
+				if _, ok := exprs[name]; !ok {
+					t.Errorf("%s is expected but not evaluated", name)
+				}
+			}
+		})
+	}
+}
+
+func TestFastBurnAlert(t *testing.T) {
+	tests := []struct {
+		name   string
+		series string
+		at     time.Duration
+		firing bool
+	}{
+		{
+			name: "2% errors for an hour",
+			series: `load 1m
+				http_requests_total{job="api", code="200"} 0+98x60
+				http_requests_total{job="api", code="500"} 0+2x60`,
+			at:     time.Hour,
+			firing: true,
+		},
+		{
+			name: "0.5% errors for an hour stays under both factors",
+			series: `load 1m
+				http_requests_total{job="api", code="200"} 0+199x60
+				http_requests_total{job="api", code="500"} 0+1x60`,
+			at:     time.Hour,
+			firing: false,
+		},
+		{
+			name: "no error series at all",
+			series: `load 1m
+				http_requests_total{job="api", code="200"} 0+100x60`,
+			at:     time.Hour,
+			firing: false,
+		},
+		{
+			name: "resets once both short windows are clean",
+			series: `load 1m
+				http_requests_total{job="api", code="200"} 0+98x60 5980+100x35
+				http_requests_total{job="api", code="500"} 0+2x60 120x35`,
+			at:     95 * time.Minute,
+			firing: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := promtest.Load(t, tt.series)
+			expr := newAvailability().Queries().BurnRateAlertQuery(fastBurn)
+			if got := s.Value(expr, tt.at) == 1; got != tt.firing {
+				t.Errorf("firing = %t, want %t\n%s", got, tt.firing, expr)
+			}
+		})
+	}
+}
+
+func TestSlowBurnWithoutFastBurn(t *testing.T) {
+	// 0.5% of the requests are slow against a 99.9% objective: a 5x burn rate,
+	// over the 2.8x of the slow burn but under the 5.6x and 13.44x of the fast burn
+	s := promtest.Load(t, `load 1m
+		http_request_duration_seconds_bucket{job="api", le="0.25"} 0+199x180
+		http_request_duration_seconds_count{job="api"} 0+200x180`)
+	q := newLatency().Queries()
+	at := 3 * time.Hour
+
+	if got := s.Value(q.InstantBurnRateQuery(), at); !approx(got, 5) {
+		t.Errorf("burn rate = %g, want 5", got)
+	}
+	if s.Value(q.BurnRateAlertQuery(fastBurn), at) != 0 {
+		t.Errorf("%s fires under a 5x burn rate", fastBurn.Name)
+	}
+	if s.Value(q.BurnRateAlertQuery(slowBurn), at) != 1 {
+		t.Errorf("%s doesn't fire under a 5x burn rate", slowBurn.Name)
+	}
+}
+
+func TestRemainingErrorBudget(t *testing.T) {
+	// A day of clean traffic, then an hour at 2% errors: 120 bad requests out of about 150000
+	s := promtest.Load(t, `load 1m
+		http_requests_total{job="api", code="200"} 0+100x1500
+		http_requests_total{job="api", code="500"} 0x1439 0+2x60`)
+	q := newAvailability().Queries()
+
+	got := s.Value(q.RemainingErrorBudgetQuery(), 25*time.Hour)
+	if want := 0.2; math.Abs(got-want) > 0.01 {
+		t.Errorf("remaining error budget = %g, want about %g", got, want)
+	}
+}
+
+func TestErrorBudgetTrendBetweenSamples(t *testing.T) {
+	// 2% errors from 4h30m to 7h30m, between the trend's 4h and 8h samples:
+	// 360 bad requests out of 54000, or out of the 48000 up to the trend's last sample
+	s := promtest.Load(t, `load 1m
+		http_requests_total{job="api", code="200"} 0+100x540
+		http_requests_total{job="api", code="500"} 0x270 0+2x180 360x89`)
+	q := newAvailability().Queries()
+	at := 9 * time.Hour
+
+	remaining := s.Value(q.RemainingErrorBudgetQuery(), at)
+	if want := 1 - 360.0/54000/0.001; math.Abs(remaining-want) > 0.2 {
+		t.Errorf("remaining error budget = %g, want about %g", remaining, want)
+	}
+	if trend, want := s.Value(q.ErrorBudgetTrendQuery(), at), 1-360.0/48000/0.001; math.Abs(trend-want) > 0.2 {
+		t.Errorf("error budget trend = %g, want about %g", trend, want)
+	}
+}
+
+// TestWindowQueriesCoverLongWindows checks that windows sampled at steps longer than 5m
+// still count every bad event
+func TestWindowQueriesCoverLongWindows(t *testing.T) {
+	// A 90d window is sampled every 16m: a burst of 18 bad requests from 5h6m to 5h15m
+	// falls between the 5h4m and 5h20m samples, out of 60000 requests
+	s := promtest.Load(t, `load 1m
+		http_requests_total{job="api", code="200"} 0+100x600
+		http_requests_total{job="api", code="500"} 0x305 0+2x9 18x284`)
+	availability := slo.NewAvailabilitySLO("api-availability", "API Availability", "Share of API requests served without a server error",
+		slo.Day*90, 0.999, serverErrors, requests)
+	q := availability.Queries()
+
+	if got, want := s.Value(q.SLITimeWindowQuery(), 10*time.Hour), 1-18.0/60000; math.Abs(got-want) > 0.00005 {
+		t.Errorf("SLI over 90d = %g, want about %g", got, want)
+	}
+}
+
+func TestTemplatedQueries(t *testing.T) {
+	s := promtest.Load(t, `load 1m
+		http_requests_total{job="api", code="200"} 0+98x60
+		http_requests_total{job="api", code="500"} 0+2x60
+		http_requests_total{job="worker", code="200"} 0+100x60`)
+	s.Variables = map[string]string{"job": "api"}
+
+	sli := slo.NewSLIQueries(slo.NewBadEventsSLI(`http_requests_total{job=~"$job", code=~"5.."}`, `http_requests_total{job=~"$job"}`),
+		0.999, slo.Week*4)
+	if got := s.Value(sli.SLIQuery(), time.Hour); !approx(got, 0.98) {
+		t.Errorf("SLI of job=api = %g, want 0.98", got)
+	}
+
+	s.Variables["job"] = "worker"
+	if got := s.Value(sli.SLIQuery(), time.Hour); !approx(got, 1) {
+		t.Errorf("SLI of job=worker = %g, want 1", got)
+	}
+}
+
+func approx(got, want float64) bool {
+	return math.Abs(got-want) <= 1e-6*math.Max(1, math.Abs(want))
+}
@@ -0,0 +1,33 @@
+package slo
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Recording rules generated for every SLO, told apart by their slo label
+const (
+	ErrorRateRecord = "slo:sli_error:rate5m"
+	TotalRateRecord = "slo:sli_total:rate5m"
+)
+
+// RecordedWindows are the windows an error ratio recording rule is generated for
+var RecordedWindows = []string{"5m", "30m", "1h", "2h", "6h", "1d", "3d"}
+
+// ErrorRatioRecord is the name of the error ratio recording rule over window
+func ErrorRatioRecord(window string) string {
+	return "slo:sli_error:ratio_rate" + window
+}
+
+// RecordedSelector selects the series recorded for one SLO
+func RecordedSelector(record, uid string) string {
+	return fmt.Sprintf(`%s{slo="%s"}`, record, uid)
+}
+
+// recordedErrorRatio returns the recorded error ratio over window, if there is one
+func recordedErrorRatio(uid, window string, timeWindow Window) (string, bool) {
+	if uid == "" || (!slices.Contains(RecordedWindows, window) && window != timeWindow.String()) {
+		return "", false
+	}
+	return RecordedSelector(ErrorRatioRecord(window), uid), true
+}
@@ -0,0 +1,57 @@
+package slo
+
+import "fmt"
+
+// SLI defines the events an SLO counts, as per-second rates averaged over a range such as 5m.
+// Every dashboard query, alert and recording rule is derived from it.
+type SLI interface {
+	GoodEvents(window string) string
+	BadEvents(window string) string
+	TotalEvents(window string) string
+}
+
+// BadEventsSLI counts failed requests out of all requests, as availability SLOs do
+type BadEventsSLI struct {
+	Bad   string
+	Total string
+}
+
+func NewBadEventsSLI(bad, total string) *BadEventsSLI {
+	return &BadEventsSLI{Bad: bad, Total: total}
+}
+
+// BadEvents falls back to 0 while requests are served but no error series exists yet
+func (s *BadEventsSLI) BadEvents(window string) string {
+	return fmt.Sprintf(`sum(rate(%s[%s])) or 0 * %s`, s.Bad, window, s.TotalEvents(window))
+}
+
+func (s *BadEventsSLI) GoodEvents(window string) string {
+	return fmt.Sprintf(`%s - (%s)`, s.TotalEvents(window), s.BadEvents(window))
+}
+
+func (s *BadEventsSLI) TotalEvents(window string) string {
+	return fmt.Sprintf(`sum(rate(%s[%s]))`, s.Total, window)
+}
+
+// GoodEventsSLI counts requests that met the objective out of all requests, as latency SLOs do
+type GoodEventsSLI struct {
+	Good  string
+	Total string
+}
+
+func NewGoodEventsSLI(good, total string) *GoodEventsSLI {
+	return &GoodEventsSLI{Good: good, Total: total}
+}
+
+// GoodEvents falls back to 0 while requests are served but no good series exists yet
+func (s *GoodEventsSLI) GoodEvents(window string) string {
+	return fmt.Sprintf(`sum(rate(%s[%s])) or 0 * %s`, s.Good, window, s.TotalEvents(window))
+}
+
+func (s *GoodEventsSLI) BadEvents(window string) string {
+	return fmt.Sprintf(`%s - (%s)`, s.TotalEvents(window), s.GoodEvents(window))
+}
+
+func (s *GoodEventsSLI) TotalEvents(window string) string {
+	return fmt.Sprintf(`sum(rate(%s[%s]))`, s.Total, window)
+}
@@ -0,0 +1,43 @@
+package slo
+
+// SLO is implemented by every kind of SLO that can be turned into a dashboard
+type SLO interface {
+	Info() Info
+	Queries() Queries
+	BuildJSON() (string, error)
+}
+
+// Info describes an SLO independently of its kind
+type Info struct {
+	UID         string
+	Name        string
+	Description string
+	Kind        string
+	TimeWindow  Window
+	Target      float64
+	SLI         SLI
+	Labels      map[string]string
+	Datasource  Datasource
+	BurnRate    BurnRatePolicy
+}
+
+// Queries are the PromQL expressions every kind of SLO provides
+type Queries interface {
+	SLIQuery() string
+	SLITimeWindowQuery() string
+	BurnRateConditionQuery(alert BurnRateAlert) string
+	BurnRateAlertQuery(alert BurnRateAlert) string
+	WindowBurnRateQuery(window string) string
+	ErrorRatioQuery(window string) string
+	ErrorRateQuery() string
+	TotalRateQuery() string
+	TimeWindowQuery() string
+	SLOTargetQuery() string
+	ErrorBudgetTrendQuery() string
+	RemainingErrorBudgetQuery() string
+	BurnRateQuery() string
+	InstantBurnRateQuery() string
+	EventRateQuery() string
+	BurndownFailureEventsQuery() string
+	BurndownTotalEventsQuery() string
+}
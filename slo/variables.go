@@ -0,0 +1,108 @@
+package slo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana-foundation-sdk/go/dashboard"
+)
+
+// VariableKind is the type of a dashboard template variable
+type VariableKind string
+
+const (
+	QueryVariable      VariableKind = "query"
+	CustomVariable     VariableKind = "custom"
+	ConstantVariable   VariableKind = "constant"
+	DatasourceVariable VariableKind = "datasource"
+)
+
+// Variable is a dashboard template variable, referenced as $Name in the panel queries
+type Variable struct {
+	Kind  VariableKind
+	Name  string
+	Label string
+
+	// Query lists the options of a query variable, e.g. label_values(up, job)
+	Query string
+
+	// Values are the options of a custom variable, or the single value of a constant
+	Values []string
+
+	// Current is the option selected when the dashboard opens
+	Current string
+
+	// Multi allows selecting several options, IncludeAll adds an "All" option
+	Multi      bool
+	IncludeAll bool
+
+	// Datasource is the datasource a query variable runs against,
+	// and the plugin type whose datasources a datasource variable lists
+	Datasource Datasource
+}
+
+// NewQueryVariable lists the values a Prometheus label takes, as returned by query
+func NewQueryVariable(name, query, current string, ds Datasource) Variable {
+	return Variable{Kind: QueryVariable, Name: name, Label: name, Query: query, Current: current, Datasource: ds}
+}
+
+// NewCustomVariable offers a fixed list of values
+func NewCustomVariable(name string, values []string, current string) Variable {
+	return Variable{Kind: CustomVariable, Name: name, Label: name, Values: values, Current: current}
+}
+
+// NewConstantVariable is a hidden variable with a single value
+func NewConstantVariable(name, value string) Variable {
+	return Variable{Kind: ConstantVariable, Name: name, Values: []string{value}}
+}
+
+// LabelValuesQuery lists the values label takes in the series matching selector
+func LabelValuesQuery(selector, label string) string {
+	return fmt.Sprintf("label_values(%s, %s)", selector, label)
+}
+
+// WithVariable adds a template variable. Unknown kinds are recorded as errors returned by Build and ToJSON.
+func (d *Dashboard) WithVariable(v Variable) *Dashboard {
+	switch v.Kind {
+	case QueryVariable:
+		variable := dashboard.NewQueryVariableBuilder(v.Name).
+			Label(v.Label).
+			Query(dashboard.StringOrMap{String: &v.Query}).
+			Datasource(dashboard.DataSourceRef{Type: &v.Datasource.Type, Uid: stringPtr(v.Datasource.panelUID())}).
+			Refresh(dashboard.VariableRefreshOnTimeRangeChanged).
+			Sort(dashboard.VariableSortAlphabeticalAsc).
+			Multi(v.Multi).
+			IncludeAll(v.IncludeAll)
+		if v.Current != "" {
+			variable = variable.Current(variableOption(v.Current))
+		}
+		d.builder = d.builder.WithVariable(variable)
+	case CustomVariable:
+		values := strings.Join(v.Values, ",")
+		variable := dashboard.NewCustomVariableBuilder(v.Name).
+			Label(v.Label).
+			Values(dashboard.StringOrMap{String: &values}).
+			Multi(v.Multi).
+			IncludeAll(v.IncludeAll)
+		if v.Current != "" {
+			variable = variable.Current(variableOption(v.Current))
+		}
+		d.builder = d.builder.WithVariable(variable)
+	case ConstantVariable:
+		value := strings.Join(v.Values, ",")
+		d.builder = d.builder.WithVariable(dashboard.NewConstantVariableBuilder(v.Name).
+			Value(dashboard.StringOrMap{String: &value}))
+	case DatasourceVariable:
+		d.WithDatasourceVariable(v.Name, v.Datasource.Type, v.Current)
+	default:
+		d.errs = append(d.errs, fmt.Errorf("variable %q: unknown kind %q", v.Name, v.Kind))
+	}
+	return d
+}
+
+func variableOption(value string) dashboard.VariableOption {
+	return dashboard.VariableOption{
+		Text:  dashboard.StringOrArrayOfString{String: &value},
+		Value: dashboard.StringOrArrayOfString{String: &value},
+	}
+}
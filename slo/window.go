@@ -0,0 +1,95 @@
+package slo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Window is the period an SLO is evaluated over, e.g. 28d
+type Window time.Duration
+
+// Units to build windows in code, e.g. 28 * Day
+const (
+	Minute = Window(time.Minute)
+	Hour   = Window(time.Hour)
+	Day    = 24 * Hour
+	Week   = 7 * Day
+)
+
+type windowUnit struct {
+	suffix byte
+	name   string
+	size   time.Duration
+}
+
+var windowUnits = []windowUnit{
+	{'w', "week", time.Duration(Week)},
+	{'d', "day", time.Duration(Day)},
+	{'h', "hour", time.Duration(Hour)},
+	{'m', "minute", time.Duration(Minute)},
+}
+
+// ParseWindow parses a Prometheus style duration with a single unit, such as 30m, 6h, 28d or 4w
+func ParseWindow(s string) (Window, error) {
+	if len(s) >= 2 && s[0] >= '1' && s[0] <= '9' {
+		count, err := strconv.ParseInt(s[:len(s)-1], 10, 64)
+		for _, unit := range windowUnits {
+			if err == nil && unit.suffix == s[len(s)-1] && count > 0 && count <= math.MaxInt64/int64(unit.size) {
+				return Window(time.Duration(count) * unit.size), nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("window %q must be a duration such as 28d", s)
+}
+
+// String renders the window as a PromQL range, in days rather than weeks so 28d stays 28d
+func (w Window) String() string {
+	count, unit := w.split()
+	return strconv.FormatInt(count, 10) + string(unit.suffix)
+}
+
+// Describe renders the window for humans, e.g. "28 days"
+func (w Window) Describe() string {
+	count, unit := w.split()
+	name := unit.name
+	if count != 1 {
+		name += "s"
+	}
+	return strconv.FormatInt(count, 10) + " " + name
+}
+
+// Step is the subquery resolution used to integrate the SLI over the window.
+// A 28d window is sampled every 5m; other windows keep the same number of points.
+func (w Window) Step() Window {
+	return w.step(8064)
+}
+
+// TrendStep is the coarser resolution of panels that chart the whole window over time, 4h for 28d
+func (w Window) TrendStep() Window {
+	return w.step(168)
+}
+
+// rateRange is the range of the rates sampled every step w: the step itself, so the samples
+// cover the window without gaps, but at least 5m so each rate spans several scrapes
+func (w Window) rateRange() Window {
+	return max(w, 5*Minute)
+}
+
+func (w Window) step(points int64) Window {
+	step := (time.Duration(w) / time.Duration(points)).Round(time.Minute)
+	return Window(max(step, time.Minute))
+}
+
+// split picks the largest unit below weeks that divides the window evenly
+func (w Window) split() (int64, windowUnit) {
+	d := time.Duration(w)
+	for _, unit := range windowUnits[1:] {
+		if d%unit.size == 0 {
+			return int64(d / unit.size), unit
+		}
+	}
+	minute := windowUnits[len(windowUnits)-1]
+	return int64(d / minute.size), minute
+}
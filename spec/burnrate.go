@@ -0,0 +1,103 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// BurnRateAlert is one multi-window burn-rate alert of a policy replacing the default one
+type BurnRateAlert struct {
+	Name     string           `yaml:"name" json:"name"`
+	Title    string           `yaml:"title" json:"title"`
+	Severity string           `yaml:"severity" json:"severity"`
+	For      string           `yaml:"for" json:"for"`
+	Windows  []BurnRateWindow `yaml:"windows" json:"windows"`
+}
+
+// BurnRateWindow fires when BudgetPercent of the error budget is spent over Long, confirmed over Short
+type BurnRateWindow struct {
+	Short         string  `yaml:"short" json:"short"`
+	Long          string  `yaml:"long" json:"long"`
+	BudgetPercent float64 `yaml:"budget_percent" json:"budget_percent"`
+}
+
+// burnRatePolicy parses the SLO's burn-rate alerts, nil meaning the default policy
+func (s *SLO) burnRatePolicy() (slo.BurnRatePolicy, error) {
+	if s.BurnRate == nil {
+		return nil, nil
+	}
+
+	var errs []error
+	policy := make(slo.BurnRatePolicy, 0, len(s.BurnRate))
+	for i, a := range s.BurnRate {
+		alert, err := a.build(s.Window)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("burn_rate[%d]: %w", i, err))
+			continue
+		}
+		policy = append(policy, alert)
+	}
+	if len(policy) == 0 && len(errs) == 0 {
+		errs = append(errs, errors.New("burn_rate needs at least one alert, omit it for the default policy"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func (a BurnRateAlert) build(sloWindow string) (slo.BurnRateAlert, error) {
+	var errs []error
+	if a.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if a.Severity == "" {
+		errs = append(errs, errors.New("severity is required"))
+	}
+	if len(a.Windows) == 0 {
+		errs = append(errs, errors.New("windows needs at least one short/long pair"))
+	}
+
+	var pending time.Duration
+	if a.For != "" {
+		var err error
+		if pending, err = time.ParseDuration(a.For); err != nil || pending < 0 {
+			errs = append(errs, fmt.Errorf("for %q must be a duration such as 2m", a.For))
+		}
+	}
+
+	// An invalid SLO window is reported by Validate already
+	timeWindow, _ := slo.ParseWindow(sloWindow)
+
+	windows := make([]slo.BurnRateWindow, 0, len(a.Windows))
+	for i, w := range a.Windows {
+		short, shortErr := slo.ParseWindow(w.Short)
+		long, longErr := slo.ParseWindow(w.Long)
+		switch {
+		case shortErr != nil || longErr != nil:
+			errs = append(errs, fmt.Errorf("windows[%d]: %w", i, errors.Join(shortErr, longErr)))
+		case short >= long:
+			errs = append(errs, fmt.Errorf("windows[%d]: short window %s must be shorter than long window %s", i, short, long))
+		case timeWindow != 0 && long > timeWindow:
+			errs = append(errs, fmt.Errorf("windows[%d]: long window %s exceeds the SLO window %s", i, long, timeWindow))
+		}
+		if w.BudgetPercent <= 0 || w.BudgetPercent > 100 {
+			errs = append(errs, fmt.Errorf("windows[%d]: budget_percent %v must be between 0 (exclusive) and 100", i, w.BudgetPercent))
+		}
+		windows = append(windows, slo.BurnRateWindow{Short: short, Long: long, BudgetConsumed: w.BudgetPercent / 100})
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return slo.BurnRateAlert{}, err
+	}
+	return slo.BurnRateAlert{
+		Name:     a.Name,
+		Title:    a.Title,
+		Severity: a.Severity,
+		For:      pending,
+		Windows:  windows,
+	}, nil
+}
@@ -0,0 +1,129 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadDir loads every .yaml, .yml and .json spec file in dir, sorted by file name.
+// Files that fail to parse are skipped and reported together in the returned error.
+func LoadDir(dir string) ([]*SLO, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !isSpecFile(entry.Name()) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+
+	var slos []*SLO
+	var errs []error
+	for _, path := range paths {
+		fileSLOs, err := LoadFile(path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		slos = append(slos, fileSLOs...)
+	}
+
+	return slos, errors.Join(errs...)
+}
+
+// LoadFile parses a single spec file and applies its shared defaults to each SLO
+func LoadFile(path string) ([]*SLO, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	for i, s := range file.SLOs {
+		if s == nil {
+			return nil, fmt.Errorf("%s: slos[%d] is empty", path, i)
+		}
+		if s.Service == "" {
+			s.Service = file.Service
+		}
+		if s.Owner == "" {
+			s.Owner = file.Owner
+		}
+		if s.Datasource == nil {
+			s.Datasource = file.Datasource
+		}
+		if s.BurnRate == nil {
+			s.BurnRate = file.BurnRate
+		}
+		if s.Variables == nil {
+			s.Variables = file.Variables
+		}
+		s.Matchers = append(slices.Clone(file.Matchers), s.Matchers...)
+		s.Source = path
+	}
+
+	return file.SLOs, nil
+}
+
+// LoadPolicy reads the file-level burn_rate of a spec file, to replay a policy before adopting it
+func LoadPolicy(path string) ([]BurnRateAlert, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := parse(path, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(file.BurnRate) == 0 {
+		return nil, fmt.Errorf("%s: burn_rate lists no alert", path)
+	}
+	return file.BurnRate, nil
+}
+
+func parse(path string, data []byte) (*File, error) {
+	file := &File{}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(file); err != nil {
+			return nil, err
+		}
+		return file, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(file); err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+func isSpecFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
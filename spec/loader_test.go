@@ -0,0 +1,121 @@
+package spec
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeSpec(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileDefaults(t *testing.T) {
+	path := writeSpec(t, t.TempDir(), "api.yaml", `
+service: api
+owner: platform
+matchers:
+  - environment="production"
+datasource:
+  uid: prometheus
+slos:
+  - uid: api-availability
+    name: API availability
+    kind: availability
+    target: 0.999
+    window: 28d
+    matchers:
+      - job="api"
+    metrics:
+      bad: http_requests_total{code=~"5.."}
+      total: http_requests_total
+  - uid: web-availability
+    name: Web availability
+    service: web
+    owner: frontend
+    kind: availability
+    target: 0.99
+    window: 7d
+    datasource:
+      uid: thanos
+    metrics:
+      bad: web_errors_total
+      total: web_requests_total
+`)
+
+	slos, err := LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(slos) != 2 {
+		t.Fatalf("got %d SLOs, want 2", len(slos))
+	}
+
+	api, web := slos[0], slos[1]
+	if api.Service != "api" || api.Owner != "platform" || api.Datasource.UID != "prometheus" || api.Source != path {
+		t.Errorf("api: got service %q, owner %q, datasource %+v, source %q, want the file's", api.Service, api.Owner, api.Datasource, api.Source)
+	}
+	if want := []string{`environment="production"`, `job="api"`}; !slices.Equal(api.Matchers, want) {
+		t.Errorf("api: matchers %q, want %q", api.Matchers, want)
+	}
+	if web.Service != "web" || web.Owner != "frontend" || web.Datasource.UID != "thanos" {
+		t.Errorf("web: got service %q, owner %q, datasource %+v, want its own", web.Service, web.Owner, web.Datasource)
+	}
+	if err := Validate(slos); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestLoadFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"unknown.yaml", "service: api\nslos:\n  - uid: api\n    trget: 0.99\n", "field trget not found"},
+		{"unknown-top-level.yaml", "servce: api\n", "field servce not found"},
+		{"unknown.json", `{"slos": [{"uid": "api", "trget": 0.99}]}`, `unknown field "trget"`},
+		{"empty.yaml", "slos:\n  -\n", "slos[0] is empty"},
+		{"syntax.yaml", "slos: [", "did not find expected node content"},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := writeSpec(t, dir, tt.name, tt.content)
+		_, err := LoadFile(path)
+		if err == nil || !strings.Contains(err.Error(), path) || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one mentioning the file and %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	writeSpec(t, dir, "b.yml", "slos:\n  - uid: b\n")
+	writeSpec(t, dir, "a.json", `{"slos": [{"uid": "a"}]}`)
+	writeSpec(t, dir, "broken.yaml", "slos:\n  - uid: broken\n    unknown: true\n")
+	writeSpec(t, dir, "README.md", "not a spec")
+	if err := os.Mkdir(filepath.Join(dir, "nested.yaml"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	slos, err := LoadDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "broken.yaml") {
+		t.Errorf("got error %v, want broken.yaml reported", err)
+	}
+
+	var uids []string
+	for _, s := range slos {
+		uids = append(uids, s.UID)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(uids, want) {
+		t.Errorf("loaded %q, want the valid files in name order %q", uids, want)
+	}
+}
@@ -0,0 +1,138 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// Metrics holds the PromQL selectors the SLI is computed from.
+// Latency SLOs use Histogram and Threshold, or Good and Total; availability SLOs use Bad and Total.
+type Metrics struct {
+	Good  string `yaml:"good" json:"good"`
+	Bad   string `yaml:"bad" json:"bad"`
+	Total string `yaml:"total" json:"total"`
+
+	// Histogram is the base name of a histogram, e.g. http_duration_milliseconds,
+	// whose bucket at Threshold counts the good events and whose _count the total
+	Histogram string `yaml:"histogram" json:"histogram"`
+	Threshold string `yaml:"threshold" json:"threshold"`
+
+	// Unit of the histogram buckets, seconds or milliseconds.
+	// Defaults to the unit suffix of the histogram name.
+	Unit string `yaml:"unit" json:"unit"`
+}
+
+// selectors are the rendered series of an SLI, with the shared matchers applied
+type selectors struct {
+	good      string
+	bad       string
+	total     string
+	histogram *promql.Histogram
+	unit      slo.HistogramUnit
+	threshold time.Duration
+}
+
+func (s *SLO) selectors() (selectors, error) {
+	base, err := parseMatchers(s.Matchers)
+	result, selectorsErr := s.selectorsWith(base)
+	return result, errors.Join(err, selectorsErr)
+}
+
+// selectorsWith renders the series of the SLI on top of the given shared matchers
+func (s *SLO) selectorsWith(base []promql.Matcher) (selectors, error) {
+	var errs []error
+
+	parse := func(field, input string) string {
+		if input == "" {
+			return ""
+		}
+		selector, err := promql.ParseSelector(input)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("metrics.%s: %w", field, err))
+			return ""
+		}
+		return selector.Merge(base).String()
+	}
+
+	var result selectors
+	if s.Metrics.Histogram != "" {
+		selector, err := promql.ParseSelector(s.Metrics.Histogram)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("metrics.histogram: %w", err))
+		} else {
+			histogram := promql.NewHistogram(selector.Metric, selector.Matchers...).Merge(base)
+			result.histogram = &histogram
+			result.total = histogram.Count().String()
+			result.unit, err = s.Metrics.histogramUnit(selector.Metric)
+			if err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		result.threshold, err = time.ParseDuration(s.Metrics.Threshold)
+		if err != nil || result.threshold <= 0 {
+			errs = append(errs, fmt.Errorf("metrics.threshold %q must be a positive duration such as 250ms", s.Metrics.Threshold))
+		}
+	} else {
+		result.good = parse("good", s.Metrics.Good)
+		result.total = parse("total", s.Metrics.Total)
+	}
+	result.bad = parse("bad", s.Metrics.Bad)
+
+	return result, errors.Join(errs...)
+}
+
+// sli is the SLI the selectors define for an SLO of the given kind
+func (sel selectors) sli(kind Kind) slo.SLI {
+	switch {
+	case kind == KindAvailability:
+		return slo.NewBadEventsSLI(sel.bad, sel.total)
+	case sel.histogram != nil:
+		return slo.NewGoodEventsSLI(sel.histogram.Bucket(sel.unit.Bucket(sel.threshold)).String(), sel.total)
+	default:
+		return slo.NewGoodEventsSLI(sel.good, sel.total)
+	}
+}
+
+func (m Metrics) histogramUnit(metric string) (slo.HistogramUnit, error) {
+	switch {
+	case m.Unit == string(slo.Seconds) || m.Unit == string(slo.Milliseconds):
+		return slo.HistogramUnit(m.Unit), nil
+	case m.Unit != "":
+		return "", fmt.Errorf("metrics.unit %q must be %q or %q", m.Unit, slo.Seconds, slo.Milliseconds)
+	case strings.HasSuffix(metric, "_milliseconds"):
+		return slo.Milliseconds, nil
+	case strings.HasSuffix(metric, "_seconds"):
+		return slo.Seconds, nil
+	default:
+		return "", fmt.Errorf("metrics.unit is required, %q has no _seconds or _milliseconds suffix", metric)
+	}
+}
+
+// parseMatchers parses matchers in order; a later matcher on the same label replaces an earlier one
+func parseMatchers(inputs []string) ([]promql.Matcher, error) {
+	var matchers []promql.Matcher
+	var errs []error
+
+	index := map[string]int{}
+	for _, input := range inputs {
+		matcher, err := promql.ParseMatcher(input)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("matchers: %w", err))
+			continue
+		}
+		if i, ok := index[matcher.Label]; ok {
+			matchers[i] = matcher
+			continue
+		}
+		index[matcher.Label] = len(matchers)
+		matchers = append(matchers, matcher)
+	}
+
+	return matchers, errors.Join(errs...)
+}
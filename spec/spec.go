@@ -0,0 +1,183 @@
+package spec
+
+import (
+	"fmt"
+	"time"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// Kind is the type of service level indicator an SLO is measured with
+type Kind string
+
+const (
+	KindLatency      Kind = "latency"
+	KindAvailability Kind = "availability"
+)
+
+// File is the on-disk layout of a spec file: shared defaults plus a list of SLOs
+type File struct {
+	Service    string          `yaml:"service" json:"service"`
+	Owner      string          `yaml:"owner" json:"owner"`
+	Datasource *Datasource     `yaml:"datasource" json:"datasource"`
+	Matchers   []string        `yaml:"matchers" json:"matchers"`
+	Variables  []Variable      `yaml:"variables" json:"variables"`
+	BurnRate   []BurnRateAlert `yaml:"burn_rate" json:"burn_rate"`
+	SLOs       []*SLO          `yaml:"slos" json:"slos"`
+}
+
+// SLO is the declarative description of a single service level objective
+type SLO struct {
+	UID         string  `yaml:"uid" json:"uid"`
+	Name        string  `yaml:"name" json:"name"`
+	DisplayName string  `yaml:"display_name" json:"display_name"`
+	Description string  `yaml:"description" json:"description"`
+	Service     string  `yaml:"service" json:"service"`
+	Operation   string  `yaml:"operation" json:"operation"`
+	Owner       string  `yaml:"owner" json:"owner"`
+	Kind        Kind    `yaml:"kind" json:"kind"`
+	Target      float64 `yaml:"target" json:"target"`
+	Window      string  `yaml:"window" json:"window"`
+
+	// CreatedAt is when the SLO was introduced, as a date or an RFC 3339 timestamp.
+	// Availability dashboards show the SLI from before it as a separate series; latency SLOs reject it.
+	CreatedAt string `yaml:"created_at" json:"created_at"`
+
+	Metrics Metrics `yaml:"metrics" json:"metrics"`
+
+	// Matchers such as environment="production" are added to every metric selector.
+	// They extend the file's matchers, replacing any on the same label.
+	Matchers []string `yaml:"matchers" json:"matchers"`
+
+	// Variables turn the matchers on some labels into dashboard variables,
+	// falling back to the file's variables
+	Variables []Variable `yaml:"variables" json:"variables"`
+
+	// RecordingRules makes the dashboard and alerts read the generated recording rules,
+	// which have to be deployed before the dashboard
+	RecordingRules bool `yaml:"recording_rules" json:"recording_rules"`
+
+	// Datasource defaults to the file's datasource, then to the generator's
+	Datasource *Datasource `yaml:"datasource" json:"datasource"`
+
+	// BurnRate replaces the default burn-rate policy of the dashboard and alerts,
+	// falling back to the file's policy
+	BurnRate []BurnRateAlert `yaml:"burn_rate" json:"burn_rate"`
+
+	// Source is the file the SLO was loaded from
+	Source string `yaml:"-" json:"-"`
+}
+
+// Datasource is the Prometheus-compatible datasource the dashboard queries
+type Datasource struct {
+	Type     string `yaml:"type" json:"type"`
+	UID      string `yaml:"uid" json:"uid"`
+	Variable bool   `yaml:"variable" json:"variable"`
+}
+
+func (ds *Datasource) toSLO() slo.Datasource {
+	if ds == nil {
+		return slo.DefaultDatasource
+	}
+
+	pluginType := ds.Type
+	if pluginType == "" {
+		pluginType = slo.DefaultDatasource.Type
+	}
+	return slo.Datasource{Type: pluginType, UID: ds.UID, Variable: ds.Variable}
+}
+
+// Build turns the spec into the matching SLO dashboard generator
+func (s *SLO) Build() (slo.SLO, error) {
+	description := s.Description
+	if description == "" {
+		description = s.Name
+	}
+
+	window, err := slo.ParseWindow(s.Window)
+	if err != nil {
+		return nil, err
+	}
+	selectors, err := s.selectors()
+	if err != nil {
+		return nil, err
+	}
+	policy, err := s.burnRatePolicy()
+	if err != nil {
+		return nil, err
+	}
+	templated, variables, err := s.templating()
+	if err != nil {
+		return nil, err
+	}
+	createdAt, err := s.createdAt()
+	if err != nil {
+		return nil, err
+	}
+
+	switch s.Kind {
+	case KindLatency:
+		var latency *slo.LatencySLO
+		if selectors.histogram != nil {
+			latency = slo.NewThresholdLatencySLO(s.UID, s.DisplayName, window, s.Target, *selectors.histogram, selectors.unit, selectors.threshold)
+		} else {
+			latency = slo.NewLatencySLO(s.UID, s.Name, description, window, s.Target, selectors.good, selectors.total)
+		}
+		latency.WithLabels(s.Labels()).WithDatasource(s.Datasource.toSLO())
+		if policy != nil {
+			latency.WithBurnRatePolicy(policy)
+		}
+		if s.RecordingRules {
+			latency.WithRecordingRules()
+		}
+		if templated != nil {
+			latency.WithTemplating(templated, variables...)
+		}
+		return latency, nil
+	case KindAvailability:
+		availability := slo.NewAvailabilitySLO(s.UID, s.Name, description, window, s.Target, selectors.bad, selectors.total).
+			WithLabels(s.Labels()).
+			WithDatasource(s.Datasource.toSLO()).
+			WithCreatedAt(createdAt)
+		if policy != nil {
+			availability.WithBurnRatePolicy(policy)
+		}
+		if s.RecordingRules {
+			availability.WithRecordingRules()
+		}
+		if templated != nil {
+			availability.WithTemplating(templated, variables...)
+		}
+		return availability, nil
+	default:
+		return nil, fmt.Errorf("unknown SLO kind %q", s.Kind)
+	}
+}
+
+// createdAt parses CreatedAt, the zero time when unset
+func (s *SLO) createdAt() (time.Time, error) {
+	if s.CreatedAt == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		if t, err := time.Parse(layout, s.CreatedAt); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("created_at %q must be a date such as 2025-01-31 or an RFC 3339 timestamp", s.CreatedAt)
+}
+
+// Labels identify the SLO's ownership in generated alerts
+func (s *SLO) Labels() map[string]string {
+	labels := map[string]string{}
+	if s.Service != "" {
+		labels["service"] = s.Service
+	}
+	if s.Operation != "" {
+		labels["operation"] = s.Operation
+	}
+	if s.Owner != "" {
+		labels["owner"] = s.Owner
+	}
+	return labels
+}
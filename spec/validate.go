@@ -0,0 +1,127 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+var uidPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,40}$`)
+
+// Validate checks every SLO and that no two SLOs share a UID
+func Validate(slos []*SLO) error {
+	var errs []error
+	for _, s := range slos {
+		if err := s.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, CheckUnique(slos))
+
+	return errors.Join(errs...)
+}
+
+// CheckUnique reports every SLO whose UID was already used by an earlier one
+func CheckUnique(slos []*SLO) error {
+	var errs []error
+	seen := map[string]string{}
+
+	for _, s := range slos {
+		if source, ok := seen[s.UID]; ok && s.UID != "" {
+			errs = append(errs, fmt.Errorf("%s: duplicate uid %q, already defined in %s", s.Source, s.UID, source))
+			continue
+		}
+		seen[s.UID] = s.Source
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate checks that the SLO has everything needed to build its dashboard
+func (s *SLO) Validate() error {
+	var errs []error
+
+	if !uidPattern.MatchString(s.UID) {
+		errs = append(errs, fmt.Errorf("uid %q must be 1-40 letters, digits, '-' or '_'", s.UID))
+	}
+	if s.Service == "" {
+		errs = append(errs, errors.New("service is required"))
+	}
+	if !(s.Target > 0 && s.Target < 1) {
+		errs = append(errs, fmt.Errorf("target %v must be between 0 and 1 (exclusive)", s.Target))
+	}
+	if _, err := slo.ParseWindow(s.Window); err != nil {
+		errs = append(errs, err)
+	}
+	if s.Datasource != nil && s.Datasource.UID == "" && !s.Datasource.Variable {
+		errs = append(errs, errors.New("datasource.uid is required unless datasource.variable is set"))
+	}
+
+	if _, err := s.burnRatePolicy(); err != nil {
+		errs = append(errs, err)
+	}
+
+	switch s.Kind {
+	case KindLatency:
+		if s.Metrics.Histogram != "" {
+			errs = append(errs, s.validateGeneratedText()...)
+			if s.Metrics.Good != "" || s.Metrics.Total != "" {
+				errs = append(errs, errors.New("metrics.histogram replaces metrics.good and metrics.total"))
+			}
+		} else {
+			errs = append(errs, s.validateName()...)
+			if s.Metrics.Good == "" || s.Metrics.Total == "" {
+				errs = append(errs, errors.New("latency SLOs need metrics.histogram and metrics.threshold, or metrics.good and metrics.total"))
+			}
+		}
+		if s.CreatedAt != "" {
+			errs = append(errs, errors.New("created_at is only supported by availability SLOs"))
+		}
+	case KindAvailability:
+		errs = append(errs, s.validateName()...)
+		if s.Metrics.Bad == "" || s.Metrics.Total == "" {
+			errs = append(errs, errors.New("metrics.bad and metrics.total are required for availability SLOs"))
+		}
+		if s.Metrics.Histogram != "" {
+			errs = append(errs, errors.New("metrics.histogram is only supported by latency SLOs"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("kind %q must be %q or %q", s.Kind, KindLatency, KindAvailability))
+	}
+
+	if _, err := s.selectors(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := s.createdAt(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, _, err := s.templating(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: slo %q: %w", s.Source, s.UID, errors.Join(errs...))
+}
+
+func (s *SLO) validateName() []error {
+	if s.Name == "" {
+		return []error{errors.New("name is required")}
+	}
+	return nil
+}
+
+// validateGeneratedText checks SLOs whose title and description are derived from their threshold
+func (s *SLO) validateGeneratedText() []error {
+	var errs []error
+	if s.DisplayName == "" {
+		errs = append(errs, errors.New("display_name is required with metrics.histogram"))
+	}
+	if s.Name != "" || s.Description != "" {
+		errs = append(errs, errors.New("name and description are generated from display_name and metrics.threshold"))
+	}
+	return errs
+}
@@ -0,0 +1,124 @@
+package spec
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func validAvailability() *SLO {
+	return &SLO{
+		UID:     "api-availability",
+		Name:    "API availability",
+		Service: "api",
+		Kind:    KindAvailability,
+		Target:  0.999,
+		Window:  "28d",
+		Metrics: Metrics{Bad: `http_requests_total{code=~"5.."}`, Total: "http_requests_total"},
+		Source:  "api.yaml",
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := validAvailability().Validate(); err != nil {
+		t.Fatalf("valid SLO: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(s *SLO)
+		want   string
+	}{
+		{"uid", func(s *SLO) { s.UID = "api availability" }, "uid"},
+		{"service", func(s *SLO) { s.Service = "" }, "service is required"},
+		{"zero target", func(s *SLO) { s.Target = 0 }, "target"},
+		{"full target", func(s *SLO) { s.Target = 1 }, "target"},
+		{"NaN target", func(s *SLO) { s.Target = math.NaN() }, "target NaN"},
+		{"empty window", func(s *SLO) { s.Window = "" }, "window"},
+		{"window unit", func(s *SLO) { s.Window = "28x" }, `window "28x"`},
+		{"window zero", func(s *SLO) { s.Window = "0d" }, `window "0d"`},
+		{"window composite", func(s *SLO) { s.Window = "1d12h" }, `window "1d12h"`},
+		{"kind", func(s *SLO) { s.Kind = "throughput" }, `kind "throughput"`},
+		{"availability metrics", func(s *SLO) { s.Metrics.Bad = "" }, "metrics.bad and metrics.total"},
+		{"latency created_at", func(s *SLO) {
+			s.Kind = KindLatency
+			s.Metrics = Metrics{Good: `latency_bucket{le="0.25"}`, Total: `latency_count`}
+			s.CreatedAt = "2025-01-31"
+		}, "created_at is only supported by availability SLOs"},
+		{"created_at", func(s *SLO) { s.CreatedAt = "yesterday" }, `created_at "yesterday"`},
+		{"datasource", func(s *SLO) { s.Datasource = &Datasource{} }, "datasource.uid is required"},
+	}
+
+	for _, tt := range tests {
+		s := validAvailability()
+		tt.change(s)
+		err := s.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestValidateBurnRate(t *testing.T) {
+	valid := func() BurnRateAlert {
+		return BurnRateAlert{
+			Name:     "SLOFastBurn",
+			Severity: "page",
+			For:      "2m",
+			Windows:  []BurnRateWindow{{Short: "5m", Long: "1h", BudgetPercent: 2}},
+		}
+	}
+
+	s := validAvailability()
+	s.BurnRate = []BurnRateAlert{valid()}
+	if err := s.Validate(); err != nil {
+		t.Fatalf("valid policy: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		change func(a *BurnRateAlert)
+		want   string
+	}{
+		{"name", func(a *BurnRateAlert) { a.Name = "" }, "burn_rate[0]: name is required"},
+		{"severity", func(a *BurnRateAlert) { a.Severity = "" }, "severity is required"},
+		{"for", func(a *BurnRateAlert) { a.For = "soon" }, `for "soon"`},
+		{"negative for", func(a *BurnRateAlert) { a.For = "-2m" }, `for "-2m"`},
+		{"no windows", func(a *BurnRateAlert) { a.Windows = nil }, "windows needs at least one"},
+		{"bad window", func(a *BurnRateAlert) { a.Windows[0].Short = "5" }, `windows[0]: window "5"`},
+		{"short not shorter", func(a *BurnRateAlert) { a.Windows[0].Short = "1h" }, "must be shorter than long window"},
+		{"long beyond SLO window", func(a *BurnRateAlert) { a.Windows[0].Long = "30d" }, "exceeds the SLO window"},
+		{"no budget", func(a *BurnRateAlert) { a.Windows[0].BudgetPercent = 0 }, "budget_percent 0"},
+		{"budget above 100", func(a *BurnRateAlert) { a.Windows[0].BudgetPercent = 150 }, "budget_percent 150"},
+	}
+
+	for _, tt := range tests {
+		alert := valid()
+		tt.change(&alert)
+		s := validAvailability()
+		s.BurnRate = []BurnRateAlert{alert}
+		err := s.Validate()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: got error %v, want one mentioning %q", tt.name, err, tt.want)
+		}
+	}
+
+	s.BurnRate = []BurnRateAlert{}
+	if err := s.Validate(); err == nil || !strings.Contains(err.Error(), "burn_rate needs at least one alert") {
+		t.Errorf("empty policy: got error %v, want it rejected", err)
+	}
+}
+
+func TestValidateDuplicateUIDs(t *testing.T) {
+	first, second, other := validAvailability(), validAvailability(), validAvailability()
+	second.Source = "web.yaml"
+	other.UID = "web-availability"
+
+	err := Validate([]*SLO{first, other, second})
+	if err == nil || !strings.Contains(err.Error(), `web.yaml: duplicate uid "api-availability", already defined in api.yaml`) {
+		t.Errorf("got error %v, want the duplicate reported against the second file", err)
+	}
+	if err := Validate([]*SLO{first, other}); err != nil {
+		t.Errorf("distinct uids: %v", err)
+	}
+}
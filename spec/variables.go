@@ -0,0 +1,108 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"unobravo.com/go-obs-as-code/promql"
+	"unobravo.com/go-obs-as-code/slo"
+)
+
+// Variable turns the matcher on Label into a dashboard variable, referenced as $<label>,
+// so one dashboard can switch between the values the label takes.
+// Alerts and recording rules keep the matcher's value, which the variable defaults to.
+type Variable struct {
+	Label string `yaml:"label" json:"label"`
+
+	// Values offers a fixed list instead of the values found in the SLI's series
+	Values []string `yaml:"values" json:"values"`
+
+	// Multi allows selecting several values at once
+	Multi bool `yaml:"multi" json:"multi"`
+}
+
+var variableNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// templating builds the dashboard variables and the SLI whose selectors reference them,
+// nil when the SLO has no variables
+func (s *SLO) templating() (slo.SLI, []slo.Variable, error) {
+	if len(s.Variables) == 0 {
+		return nil, nil, nil
+	}
+
+	base, err := parseMatchers(s.Matchers)
+	if err != nil {
+		// Reported by selectors already
+		return nil, nil, nil
+	}
+
+	var errs []error
+	if s.RecordingRules {
+		errs = append(errs, errors.New("variables can't be used with recording_rules, whose series only exist for the SLO's own matchers"))
+	}
+
+	defaults := make([]string, len(s.Variables))
+	for i, v := range s.Variables {
+		switch {
+		case !variableNamePattern.MatchString(v.Label):
+			errs = append(errs, fmt.Errorf("variables[%d]: label %q isn't a valid variable name", i, v.Label))
+			continue
+		case slices.ContainsFunc(s.Variables[:i], func(other Variable) bool { return other.Label == v.Label }):
+			errs = append(errs, fmt.Errorf("variables[%d]: duplicate label %q", i, v.Label))
+			continue
+		}
+
+		j := slices.IndexFunc(base, func(m promql.Matcher) bool { return m.Label == v.Label })
+		if j < 0 || base[j].Type != promql.MatchEqual {
+			errs = append(errs, fmt.Errorf("variables[%d]: label %q needs an equality matcher in matchers to default to", i, v.Label))
+			continue
+		}
+		defaults[i] = base[j].Value
+		if len(v.Values) > 0 && !slices.Contains(v.Values, defaults[i]) {
+			errs = append(errs, fmt.Errorf("variables[%d]: values %q don't include the matcher's value %q", i, v.Values, defaults[i]))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+
+	// Each variable lists the values found under the ones before it, so they can be chained
+	var variables []slo.Variable
+	for i, v := range s.Variables {
+		var variable slo.Variable
+		if len(v.Values) > 0 {
+			variable = slo.NewCustomVariable(v.Label, v.Values, defaults[i])
+		} else {
+			sel, err := s.selectorsWith(templatedMatchers(base, s.Variables[:i], s.Variables[i:]))
+			if err != nil {
+				return nil, nil, err
+			}
+			variable = slo.NewQueryVariable(v.Label, slo.LabelValuesQuery(sel.total, v.Label), defaults[i], s.Datasource.toSLO())
+		}
+		variable.Multi = v.Multi
+		variables = append(variables, variable)
+	}
+
+	sel, err := s.selectorsWith(templatedMatchers(base, s.Variables, nil))
+	if err != nil {
+		return nil, nil, err
+	}
+	return sel.sli(s.Kind), variables, nil
+}
+
+// templatedMatchers makes the matchers on the templated labels reference their variable, and drops the ones on the dropped labels
+func templatedMatchers(base []promql.Matcher, templated, dropped []Variable) []promql.Matcher {
+	matchers := make([]promql.Matcher, 0, len(base))
+	for _, m := range base {
+		switch {
+		case slices.ContainsFunc(dropped, func(v Variable) bool { return v.Label == m.Label }):
+		case slices.ContainsFunc(templated, func(v Variable) bool { return v.Label == m.Label }):
+			matchers = append(matchers, promql.Regexp(m.Label, "$"+m.Label))
+		default:
+			matchers = append(matchers, m)
+		}
+	}
+	return matchers
+}
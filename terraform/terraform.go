@@ -0,0 +1,198 @@
+// Package terraform renders dashboards, folders and alert rules as Terraform JSON
+// for the Grafana provider.
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"unobravo.com/go-obs-as-code/grafana"
+)
+
+// Config is a *.tf.json file declaring Grafana resources
+type Config struct {
+	Resource Resources `json:"resource"`
+}
+
+// Resources are keyed by their Terraform resource name
+type Resources struct {
+	Folders    map[string]Folder    `json:"grafana_folder,omitempty"`
+	Dashboards map[string]Dashboard `json:"grafana_dashboard,omitempty"`
+	RuleGroups map[string]RuleGroup `json:"grafana_rule_group,omitempty"`
+}
+
+type Folder struct {
+	Title string `json:"title"`
+}
+
+type Dashboard struct {
+	Folder     string `json:"folder,omitempty"`
+	ConfigJSON string `json:"config_json"`
+	Overwrite  bool   `json:"overwrite"`
+}
+
+type RuleGroup struct {
+	Name            string `json:"name"`
+	FolderUID       string `json:"folder_uid"`
+	IntervalSeconds int    `json:"interval_seconds"`
+	Rules           []Rule `json:"rule"`
+}
+
+type Rule struct {
+	UID          string            `json:"uid"`
+	Name         string            `json:"name"`
+	For          string            `json:"for,omitempty"`
+	Condition    string            `json:"condition"`
+	NoDataState  string            `json:"no_data_state"`
+	ExecErrState string            `json:"exec_err_state"`
+	Labels       map[string]string `json:"labels,omitempty"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	Data         []RuleData        `json:"data"`
+}
+
+type RuleData struct {
+	RefID             string            `json:"ref_id"`
+	DatasourceUID     string            `json:"datasource_uid"`
+	RelativeTimeRange RelativeTimeRange `json:"relative_time_range"`
+	Model             string            `json:"model"`
+}
+
+// RelativeTimeRange is in seconds before the evaluation time
+type RelativeTimeRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func NewConfig() *Config {
+	return &Config{}
+}
+
+// WithFolder declares a folder under the given resource name
+func (c *Config) WithFolder(name, title string) *Config {
+	if c.Resource.Folders == nil {
+		c.Resource.Folders = map[string]Folder{}
+	}
+	c.Resource.Folders[name] = Folder{Title: title}
+	return c
+}
+
+// WithDashboard declares a dashboard, named after its UID, in the given folder resource
+func (c *Config) WithDashboard(uid, folder, dashboardJSON string) *Config {
+	if c.Resource.Dashboards == nil {
+		c.Resource.Dashboards = map[string]Dashboard{}
+	}
+	c.Resource.Dashboards[ResourceName(uid)] = Dashboard{
+		Folder:     Reference("grafana_folder", folder, "uid"),
+		ConfigJSON: Literal(dashboardJSON),
+		Overwrite:  true,
+	}
+	return c
+}
+
+// WithRuleGroup declares a rule group, named after the dashboard UID, in the given folder resource.
+// The folder of the group itself is ignored.
+func (c *Config) WithRuleGroup(uid, folder string, group grafana.AlertRuleGroup) (*Config, error) {
+	interval, err := time.ParseDuration(group.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("rule group %q: interval: %w", group.Name, err)
+	}
+
+	converted := make([]Rule, 0, len(group.Rules))
+	for _, r := range group.Rules {
+		rule, err := newRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", r.Title, err)
+		}
+		converted = append(converted, rule)
+	}
+
+	if c.Resource.RuleGroups == nil {
+		c.Resource.RuleGroups = map[string]RuleGroup{}
+	}
+	c.Resource.RuleGroups[ResourceName(uid)] = RuleGroup{
+		Name:            group.Name,
+		FolderUID:       Reference("grafana_folder", folder, "uid"),
+		IntervalSeconds: int(interval.Seconds()),
+		Rules:           converted,
+	}
+	return c, nil
+}
+
+func newRule(r grafana.AlertRule) (Rule, error) {
+	data := make([]RuleData, 0, len(r.Data))
+	for _, q := range r.Data {
+		model, err := json.Marshal(q.Model)
+		if err != nil {
+			return Rule{}, fmt.Errorf("query %s: %w", q.RefID, err)
+		}
+		data = append(data, RuleData{
+			RefID:             q.RefID,
+			DatasourceUID:     q.DatasourceUID,
+			RelativeTimeRange: RelativeTimeRange(q.RelativeTimeRange),
+			Model:             Literal(string(model)),
+		})
+	}
+
+	return Rule{
+		UID:          r.UID,
+		Name:         r.Title,
+		For:          r.For,
+		Condition:    r.Condition,
+		NoDataState:  r.NoDataState,
+		ExecErrState: r.ExecErrState,
+		Labels:       literals(r.Labels),
+		Annotations:  literals(r.Annotations),
+		Data:         data,
+	}, nil
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
+// ResourceName turns a UID into a valid resource name: letters, digits, '_' and '-',
+// not starting with a digit or '-'
+func ResourceName(uid string) string {
+	name := invalidNameChars.ReplaceAllString(uid, "_")
+	if name == "" || !isLetter(name[0]) && name[0] != '_' {
+		name = "_" + name
+	}
+	return name
+}
+
+func isLetter(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z'
+}
+
+// Reference interpolates an attribute of another resource, e.g. ${grafana_folder.slo.uid}
+func Reference(resourceType, name, attribute string) string {
+	return fmt.Sprintf("${%s.%s.%s}", resourceType, name, attribute)
+}
+
+// Literal escapes the template sequences Terraform would otherwise interpolate,
+// such as the ${datasource} variable of a dashboard
+func Literal(s string) string {
+	return templateEscaper.Replace(s)
+}
+
+var templateEscaper = strings.NewReplacer("${", "$${", "%{", "%%{")
+
+func literals(m map[string]string) map[string]string {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = Literal(v)
+	}
+	return out
+}
+
+func (c *Config) ToJSON() (string, error) {
+	jsonBytes, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(jsonBytes), nil
+}